@@ -8,17 +8,34 @@ import (
 	"log"
 	"meetingagent/einoagent"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudwego/eino-ext/callbacks/apmplus"
 	"github.com/cloudwego/eino-ext/callbacks/langfuse"
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/schema"
 
-	"github.com/cloudwego/eino-examples/quickstart/eino_assistant/pkg/mem"
+	"meetingagent/pkg/guard"
+	"meetingagent/pkg/mem"
+	"meetingagent/pkg/tool/task"
 )
 
-var memory = mem.GetDefaultMemory()
+var (
+	memoryOnce sync.Once
+	memory     mem.Memory
+	memoryErr  error
+)
+
+// Memory 进程内共享的会话记忆，配置见 einoagent.NewMemory
+func Memory() (mem.Memory, error) {
+	memoryOnce.Do(func() {
+		memory, memoryErr = einoagent.NewMemory(context.Background())
+	})
+	return memory, memoryErr
+}
 
 var cbHandler callbacks.Handler
 
@@ -70,70 +87,268 @@ func Init() error {
 		if len(callbackHandlers) > 0 {
 			callbacks.InitCallbackHandlers(callbackHandlers)
 		}
+
+		// 启动时加载提示词、构建 graph 与会话记忆，配置有误时尽早暴露
+		if _, err = einoagent.AgentPrompt(); err != nil {
+			return
+		}
+		if _, err = getRunner(context.Background()); err != nil {
+			return
+		}
+		_, err = Memory()
 	})
 	return err
 }
 
-func RunAgent(ctx context.Context, id string, msg string) (*schema.StreamReader[*schema.Message], error) {
+// runnerEnvs 构建 graph 时读取的环境变量，任一变化后下一次对话会重新构建 graph
+var runnerEnvs = []string{
+	"ARK_API_KEY",
+	"ARK_CHAT_MODEL",
+	"ARK_EMBEDDING_MODEL",
+	"RETRIEVER_TOP_K",
+	"RETRIEVER_MIN_SCORE",
+	"RETRIEVER_FILTER",
+	"TOOL_CONFIRMATION",
+	"AGENT_MAX_DURATION",
+	"AGENT_MAX_TOKENS",
+	"AGENT_MAX_TOOL_CALLS",
+	"AGENT_MAX_REPEATED_CALLS",
+	"AGENT_TOOL_TIMEOUT",
+	"AGENT_TOOL_TIMEOUTS",
+}
+
+var (
+	runnerMu     sync.RWMutex
+	runner       compose.Runnable[*einoagent.UserMessage, *schema.Message]
+	runnerConfig string
+)
+
+func currentConfig() string {
+	values := make([]string, len(runnerEnvs))
+	for i, key := range runnerEnvs {
+		values[i] = key + "=" + os.Getenv(key)
+	}
+	return strings.Join(values, "\n")
+}
+
+// getRunner 返回共享的已编译 graph。graph 及其中的 ChatModel、Embedder、检索客户端只构建一次，
+// 被所有对话复用；runnerEnvs 中的配置变化时重新构建
+func getRunner(ctx context.Context) (compose.Runnable[*einoagent.UserMessage, *schema.Message], error) {
+	config := currentConfig()
+
+	runnerMu.RLock()
+	r, built := runner, runnerConfig
+	runnerMu.RUnlock()
+	if r != nil && built == config {
+		return r, nil
+	}
+
+	runnerMu.Lock()
+	defer runnerMu.Unlock()
+	if runner != nil && runnerConfig == config {
+		return runner, nil
+	}
+	return rebuild(ctx, config)
+}
+
+// Reload 重新构建 graph，用于 .env 或外部配置更新后主动生效
+func Reload(ctx context.Context) error {
+	runnerMu.Lock()
+	defer runnerMu.Unlock()
+	_, err := rebuild(ctx, currentConfig())
+	return err
+}
 
-	runner, err := einoagent.BuildEinoAgent(ctx)
+// rebuild 调用方需持有 runnerMu 写锁，构建失败时保留旧的 graph
+func rebuild(ctx context.Context, config string) (compose.Runnable[*einoagent.UserMessage, *schema.Message], error) {
+	start := time.Now()
+	r, err := einoagent.BuildEinoAgent(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build agent graph: %w", err)
 	}
+	if runner != nil {
+		log.Printf("[eino agent] INFO: config changed, agent graph rebuilt in %v", time.Since(start))
+	}
+	runner, runnerConfig = r, config
+	return r, nil
+}
+
+// BindMeeting 记录会话所属的会议，会话已属于其他会议时不修改
+func BindMeeting(id string, meetingID string) error {
+	memory, err := Memory()
+	if err != nil {
+		return fmt.Errorf("failed to init memory: %w", err)
+	}
+	conversation := memory.GetConversation(id, true)
+	if conversation == nil {
+		return fmt.Errorf("failed to create conversation %s", id)
+	}
+	if conversation.Info().MeetingID != "" {
+		return nil
+	}
+	return conversation.UpdateInfo(func(info *mem.SessionInfo) {
+		info.MeetingID = meetingID
+	})
+}
+
+// ErrNothingToRegenerate 会话中没有可重新生成的回答
+var ErrNothingToRegenerate = errors.New("no message to regenerate")
+
+// RunAgent 运行一轮对话，opts 会透传给 graph，可用于覆盖检索参数，
+// 例如 compose.WithRetrieverOption(retriever.WithTopK(4))。
+// 取消 ctx 会中断进行中的模型调用与工具调用，已生成的部分回答会以 Extra["stopped"]=true 记入会话；
+// 尚未生成任何内容或运行出错时，这一轮不会写入会话。返回的流在这一轮写入会话之后才结束
+func RunAgent(ctx context.Context, id string, msg string, opts ...compose.Option) (*schema.StreamReader[*schema.Message], error) {
+	return run(ctx, id, msg, false, opts...)
+}
+
+// Regenerate 以会话中最后一轮的问题重新运行，新的回答生成内容后才替换这一轮，
+// 运行失败或在生成任何内容前被停止时原来的问答保持不变
+func Regenerate(ctx context.Context, id string, opts ...compose.Option) (*schema.StreamReader[*schema.Message], error) {
+	memory, err := Memory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init memory: %w", err)
+	}
+	conversation := memory.GetConversation(id, false)
+	if conversation == nil {
+		return nil, ErrNothingToRegenerate
+	}
+	msgs := conversation.GetFullMessages()
+	i := lastUserIndex(msgs)
+	if i < 0 {
+		return nil, ErrNothingToRegenerate
+	}
+	return run(ctx, id, msgs[i].Content, true, opts...)
+}
+
+// run 运行一轮对话，replace 为 true 时用这一轮替换会话中的最后一轮
+func run(ctx context.Context, id string, msg string, replace bool, opts ...compose.Option) (*schema.StreamReader[*schema.Message], error) {
+	runner, err := getRunner(context.WithoutCancel(ctx))
+	if err != nil {
+		return nil, err
+	}
 
+	memory, err := Memory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init memory: %w", err)
+	}
 	conversation := memory.GetConversation(id, true)
 
+	// 整轮对话使用同一版本的提示词，版本记录在回答中
+	prompt, err := einoagent.AgentPrompt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load prompt: %w", err)
+	}
+
+	history := conversation.GetMessages()
+	if replace {
+		// 重新回答时不带上被替换的这一轮
+		if i := lastUserIndex(history); i >= 0 {
+			history = history[:i]
+		}
+	}
 	userMessage := &einoagent.UserMessage{
 		ID:      id,
 		Query:   msg,
-		History: conversation.GetMessages(),
+		History: history,
+		Prompt:  prompt,
 	}
 
-	sr, err := runner.Stream(ctx, userMessage)
+	// 本轮中 task_manager 对任务的修改在变更历史中记录为这个对话
+	ctx = task.WithActor(ctx, task.Actor{Kind: task.ActorAgent, ID: id})
+
+	// 统计本轮的时长、token 与工具调用，超过 AGENT_* 限制时以说明结束
+	sr, err := runner.Stream(guard.WithRun(ctx), userMessage, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to stream: %w", err)
 	}
 
-	srs := sr.Copy(2)
-	log.Printf("srs=%v", srs)
-
+	// 转发给调用方的同时收集完整回答，写入会话后再结束转发的流，
+	// 调用方读到流结束时这一轮已经保存，紧接着的下一轮能看到它
+	out, writer := schema.Pipe[*schema.Message](16)
 	go func() {
-		// for save to memory
-		fullMsgs := make([]*schema.Message, 0)
-
-		defer func() {
-			// close stream if you used it
-			srs[1].Close()
-
-			// add user input to history
-			conversation.Append(schema.UserMessage(msg))
+		defer sr.Close()
 
-			fullMsg, err := schema.ConcatMessages(fullMsgs)
+		fullMsgs := make([]*schema.Message, 0)
+		// streamErr 在写入会话之后再交给调用方
+		var streamErr error
+		closed := false
+		for {
+			chunk, err := sr.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
 			if err != nil {
-				fmt.Println("error concatenating messages: ", err.Error())
+				log.Printf("[eino agent] agent stream interrupted: %v", err)
+				streamErr = err
+				break
 			}
-			// add agent response to history
-			conversation.Append(fullMsg)
-		}()
-
-	outer:
-		for {
-			select {
-			case <-ctx.Done():
-				fmt.Println("context done", ctx.Err())
-				return
-			default:
-				chunk, err := srs[1].Recv()
-				if err != nil {
-					if errors.Is(err, io.EOF) {
-						break outer
-					}
-				}
-
-				fullMsgs = append(fullMsgs, chunk)
+			fullMsgs = append(fullMsgs, chunk)
+			// 调用方不再读取时继续收集，回答仍然写入会话
+			if !closed {
+				closed = writer.Send(chunk, nil)
 			}
 		}
+
+		// 只有取消 ctx 才算停止；模型、网络或工具出错时这一轮不写入会话，重新回答时原来的问答保持不变
+		stopped := streamErr != nil && (errors.Is(streamErr, context.Canceled) || errors.Is(ctx.Err(), context.Canceled))
+		saved := false
+		if streamErr == nil || stopped {
+			saved = save(conversation, msg, fullMsgs, stopped, replace, prompt.Ref())
+		}
+		if streamErr != nil && !closed {
+			writer.Send(nil, streamErr)
+		}
+		writer.Close()
+		// 会话在这一轮结束后被删除时不再写入摘要
+		if !saved || !memory.HasConversation(id) {
+			return
+		}
+
+		// 将移出窗口的消息折叠进摘要，不影响本轮回答
+		compactCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := conversation.Compact(compactCtx); err != nil {
+			log.Printf("[eino agent] failed to compact conversation %s: %v", id, err)
+		}
 	}()
 
-	return srs[0], nil
+	return out, nil
+}
+
+// save 将这一轮写入会话，被停止且没有生成任何内容时不写入
+func save(conversation mem.Conversation, msg string, fullMsgs []*schema.Message, stopped bool, replace bool, promptRef string) bool {
+	if stopped && len(fullMsgs) == 0 {
+		return false
+	}
+
+	fullMsg, err := schema.ConcatMessages(fullMsgs)
+	if err != nil {
+		log.Printf("[eino agent] error concatenating messages: %v", err)
+		return false
+	}
+	if fullMsg.Extra == nil {
+		fullMsg.Extra = map[string]any{}
+	}
+	fullMsg.Extra["prompt_version"] = promptRef
+	if stopped {
+		fullMsg.Extra["stopped"] = true
+	}
+
+	if replace {
+		conversation.PopLastTurn()
+	}
+	// add user input and agent response to history
+	conversation.Append(schema.UserMessage(msg))
+	conversation.Append(fullMsg)
+	return true
+}
+
+func lastUserIndex(msgs []*schema.Message) int {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == schema.User {
+			return i
+		}
+	}
+	return -1
 }
@@ -3,16 +3,24 @@ package main
 import (
 	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
+	chatagent "meetingagent/cmd/einoagent/agent"
+	"meetingagent/cmd/einoagent/task"
 	"meetingagent/handlers"
+	"meetingagent/pkg/embedcache"
 	"meetingagent/pkg/env"
+	"meetingagent/pkg/reminder"
+	"meetingagent/pkg/tasksync"
 	"meetingagent/redis"
 
 	"github.com/cloudwego/eino-examples/quickstart/eino_assistant/cmd/einoagent/agent"
-	"github.com/cloudwego/eino-examples/quickstart/eino_assistant/cmd/einoagent/task"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/app/server"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
 )
 
 func init() {
@@ -23,9 +31,29 @@ func init() {
 func main() {
 
 	redis.Init()
+	if err := chatagent.Init(); err != nil {
+		log.Fatal("failed to init agent:", err)
+	}
+	go reloadOnSignal()
+
+	// 后台检查即将到期与逾期的任务并发送提醒
+	scheduler, err := reminder.Default()
+	if err != nil {
+		log.Fatal("failed to init reminders:", err)
+	}
+	go scheduler.Run(context.Background())
+
+	// 后台将任务同步到外部任务系统，SYNC_CONNECTORS 为空时不同步
+	syncer, err := tasksync.Default()
+	if err != nil {
+		log.Fatal("failed to init task sync:", err)
+	}
+	go syncer.Run(context.Background())
+
 	h := server.Default()
 	h.Use(Logger())
 
+	// 任务页面与 /task/api，与 /tasks 共用同一个 task.Service
 	taskGroup := h.Group("/task")
 	if err := task.BindRoutes(taskGroup); err != nil {
 		log.Fatal("failed to bind task routes:", err)
@@ -46,7 +74,29 @@ func main() {
 	h.POST("/meeting", handlers.CreateMeeting)
 	h.GET("/meeting", handlers.ListMeetings)
 	h.GET("/summary", handlers.GetMeetingSummary)
+	h.GET("/prompts", handlers.ListPrompts)
+	h.POST("/prompts/reload", handlers.HandleReloadPrompts)
 	h.GET("/chat", handlers.HandleChat)
+	h.POST("/chat/:session/stop", handlers.StopChat)
+	h.POST("/chat/:session/confirm", handlers.ConfirmChat)
+	h.POST("/chat/:session/regenerate", handlers.RegenerateChat)
+	h.GET("/sessions", handlers.ListSessions)
+	h.GET("/sessions/:id", handlers.GetSession)
+	h.PATCH("/sessions/:id", handlers.UpdateSession)
+	h.DELETE("/sessions/:id", handlers.DeleteSession)
+	h.GET("/tasks", handlers.ListTasks)
+	h.POST("/tasks", handlers.CreateTask)
+	h.GET("/tasks/:id", handlers.GetTask)
+	h.PATCH("/tasks/:id", handlers.UpdateTask)
+	h.DELETE("/tasks/:id", handlers.DeleteTask)
+	h.POST("/tasks/:id/restore", handlers.RestoreTask)
+	h.GET("/tasks/:id/history", handlers.GetTaskHistory)
+	h.GET("/tasks/:id/sync", handlers.GetTaskSync)
+	h.GET("/notifications", handlers.ListNotifications)
+	h.GET("/notifications/stream", handlers.StreamNotifications)
+	h.GET("/metrics/embedding_cache", func(ctx context.Context, c *app.RequestContext) {
+		c.JSON(consts.StatusOK, embedcache.GetStats())
+	})
 
 	// Serve static files
 	h.StaticFS("/", &app.FS{
@@ -60,6 +110,26 @@ func main() {
 	h.Spin()
 }
 
+// reloadOnSignal 收到 SIGHUP 时重新读取 .env 与提示词模板，并重建 agent graph
+func reloadOnSignal() {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	for range ch {
+		if err := env.Reload(); err != nil {
+			log.Printf("[reload] failed to reload .env: %v", err)
+			continue
+		}
+		if err := handlers.ReloadPrompts(); err != nil {
+			log.Printf("[reload] failed to reload prompts: %v", err)
+		}
+		if err := chatagent.Reload(context.Background()); err != nil {
+			log.Printf("[reload] failed to rebuild agent: %v", err)
+			continue
+		}
+		log.Printf("[reload] agent reloaded")
+	}
+}
+
 // Logger 记录 HTTP 请求日志
 func Logger() app.HandlerFunc {
 	return func(ctx context.Context, c *app.RequestContext) {
@@ -19,7 +19,6 @@ package task
 import (
 	"context"
 	"embed"
-	"fmt"
 	"mime"
 	"path/filepath"
 
@@ -29,7 +28,7 @@ import (
 
 	// 新增redis导入
 
-	"github.com/cloudwego/eino-examples/quickstart/eino_assistant/pkg/tool/task"
+	"meetingagent/pkg/tool/task"
 )
 
 //go:embed static/*
@@ -46,14 +45,12 @@ type TodoUpdateParams struct {
 
 // BindRoutes 注册路由
 func BindRoutes(r *route.RouterGroup) error {
-	ctx := context.Background()
-
-	taskTool, err := task.NewTaskToolImpl(ctx, &task.TaskToolConfig{
-		Storage: task.GetDefaultStorage(),
-	})
+	service, err := task.DefaultService()
 	if err != nil {
 		return err
 	}
+	// 与 agent 使用的 task_manager 工具相同的请求格式，资源形式的接口见 /tasks
+	taskTool := task.NewTaskToolFromService(service)
 
 	// API 处理
 
@@ -67,10 +64,9 @@ func BindRoutes(r *route.RouterGroup) error {
 			return
 		}
 
-		resp, err := taskTool.Invoke(ctx, &req)
+		// 页面通过这个接口批量导入会议中的待办，变更历史中记录为从来源会议导入
+		resp, err := taskTool.Invoke(task.WithActor(ctx, task.Actor{Kind: task.ActorImport}), &req)
 		if err != nil {
-			// 记录详细错误信息
-			fmt.Printf("Task API error: %v\n", err)
 			c.JSON(consts.StatusInternalServerError, map[string]interface{}{
 				"status": "error",
 				"error":  err.Error(),
@@ -78,7 +74,6 @@ func BindRoutes(r *route.RouterGroup) error {
 			return
 		}
 		c.JSON(consts.StatusOK, resp)
-		fmt.Printf("Task API response: %v\n", resp)
 	})
 
 	// 静态文件服务
@@ -324,15 +324,45 @@ async function sendMessage() {
   const assistantMsgID = Math.random().toString(36).substring(2, 15);
 
  
-  eventSource.onmessage = (event) => {
+  eventSource.addEventListener('token', (event) => {
     const data = JSON.parse(event.data);
-    // 从响应中提取消息内容
-    addMessageToChat(assistantMsgID, data.data.message, 'assistant');
-  };
+    addMessageToChat(assistantMsgID, data.message, 'assistant');
+  });
+
+  eventSource.addEventListener('tool_call', (event) => {
+    const data = JSON.parse(event.data);
+    console.debug('[chat] tool call', data.name, data.arguments);
+  });
+
+  // 删除、修改任务等操作需要用户确认，agent 会暂停直到提交决定
+  eventSource.addEventListener('confirmation_required', async (event) => {
+    const data = JSON.parse(event.data);
+    const approved = window.confirm(`助手请求执行 ${data.tool} ${data.action || ''}：\n${JSON.stringify(data.arguments, null, 2)}\n\n是否允许？`);
+    try {
+      await fetch(`${basePath}/chat/${encodeURIComponent(currentSessionId)}/confirm`, {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify({ confirmation_id: data.id, approved: approved }),
+      });
+    } catch (error) {
+      console.error('Error sending confirmation:', error);
+    }
+  });
 
-  eventSource.onerror = () => {
+  eventSource.addEventListener('done', () => {
     eventSource.close();
-  };
+  });
+
+  eventSource.addEventListener('error', (event) => {
+    // 服务端的 error 事件带有 data；连接断开时浏览器会自动带 Last-Event-ID 重连
+    if (event.data) {
+      const data = JSON.parse(event.data);
+      addMessageToChat(assistantMsgID, `\n[error] ${data.error}`, 'assistant');
+      eventSource.close();
+    } else if (eventSource.readyState === EventSource.CLOSED) {
+      eventSource.close();
+    }
+  });
 }
 
 let msgs = {};
@@ -489,6 +519,17 @@ function updateCountdown(element, deadline) {
 }
 
 
+// 部分更新任务，etag 不为空时任务在此期间被修改过会返回 412
+function patchTask(id, etag, fields) {
+    const headers = { 'Content-Type': 'application/json' };
+    if (etag) headers['If-Match'] = etag;
+    return fetch(`/tasks/${encodeURIComponent(id)}`, {
+        method: 'PATCH',
+        headers,
+        body: JSON.stringify(fields)
+    });
+}
+
 // Task 列表处理
 async function loadTasks(meetingId) {
     const params = getQueryParams();
@@ -506,56 +547,21 @@ async function loadTasks(meetingId) {
     taskListElement.innerHTML = '<p class="text-gray-500 p-4">加载中...</p>'; // Show loading state
 
     try {
-        // IMPORTANT: Adjust the fetch URL if your API endpoint is different
-        const response = await fetch('/task/api', { // Make sure this endpoint is correct
-            method: 'POST',
-            headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({
-                action: 'list', // Ensure backend expects this action for listing
-                list: params // Include meeting ID with filter params
-            })
-        });
-
+        const query = new URLSearchParams();
+        if (params.query) query.set('q', params.query);
+        if (params.is_done !== null) query.set('done', params.is_done);
+        if (params.limit) query.set('limit', params.limit);
+        const response = await fetch(`/tasks?${query}`);
+        const data = await response.json();
         if (!response.ok) {
-            // Handle HTTP errors (e.g., 404, 500)
-            throw new Error(`HTTP error! status: ${response.status}`);
+            throw new Error(data.error || `HTTP error! status: ${response.status}`);
         }
 
-        const data = await response.json();
-        console.log('Task data response:', data); // Add logging for debugging
-
-        // More flexible handling of different response formats
-        if (data.status === 'success') {
-            let tasksToRender = [];
-            
-            // Handle various possible response formats
-            if (Array.isArray(data.task_list)) {
-                tasksToRender = data.task_list;
-            } else if (data.tasks && Array.isArray(data.tasks)) {
-                tasksToRender = data.tasks;
-            } else if (data.data && Array.isArray(data.data)) {
-                tasksToRender = data.data;
-            } else if (typeof data.task_list === 'object' && data.task_list !== null) {
-                // Handle nested object cases
-                if (data.task_list.tasks && Array.isArray(data.task_list.tasks)) {
-                    tasksToRender = data.task_list.tasks;
-                } else if (data.task_list.data && Array.isArray(data.task_list.data)) {
-                    tasksToRender = data.task_list.data;
-                }
-            } else if (Array.isArray(data)) {
-                console.warn("Backend returned an array directly. Assuming it's the task list.");
-                tasksToRender = data;
-            }
-            
-            if (tasksToRender.length > 0) {
-                renderTasks(tasksToRender);
-            } else {
-                taskListElement.innerHTML = '<p class="text-center text-gray-500 my-4">没有找到任务</p>';
-            }
+        const tasksToRender = data.tasks || [];
+        if (tasksToRender.length > 0) {
+            renderTasks(tasksToRender);
         } else {
-            // Handle cases where the backend response format is unexpected
-            console.error('Failed to load tasks: Invalid response format.', data);
-            taskListElement.innerHTML = '<p class="text-red-500 p-4">加载任务失败：响应格式无效。</p>';
+            taskListElement.innerHTML = '<p class="text-center text-gray-500 my-4">没有找到任务</p>';
         }
     } catch (error) {
         console.error('Failed to load tasks:', error);
@@ -629,6 +635,8 @@ function renderTasks(tasks) {
         const deadlineEl = item.querySelector('.task-deadline');
 
         item.dataset.id = task.id;
+        // 修改与删除时作为 If-Match，避免覆盖其他人的修改
+        item.dataset.etag = task.etag || '';
         // Store the original task data for editing
         item.dataset.task = JSON.stringify(task);
 
@@ -637,6 +645,30 @@ function renderTasks(tasks) {
         if (contentEl) contentEl.textContent = task.content || '';
         if (createdEl) createdEl.textContent = `创建: ${formatDate(task.created_at)}`;
 
+        // 状态、优先级、负责人、标签与来源会议
+        const metaEl = item.querySelector('.task-meta');
+        if (metaEl) {
+            const statusText = { todo: '待办', in_progress: '进行中', blocked: '受阻', done: '已完成', cancelled: '已取消' };
+            const priorityText = { low: '低', medium: '中', high: '高', urgent: '紧急' };
+            const parts = [];
+            if (task.status) parts.push(statusText[task.status] || task.status);
+            if (task.priority) parts.push(`优先级: ${priorityText[task.priority] || task.priority}`);
+            if (task.assignee) parts.push(`负责人: ${task.assignee}`);
+            if (task.tags && task.tags.length > 0) parts.push(task.tags.map(tag => `#${tag}`).join(' '));
+            if (task.source_meeting_id) {
+                parts.push(`来源: ${task.source_meeting_id}${task.source_timestamp ? ' @' + task.source_timestamp : ''}`);
+            }
+            // 子任务完成情况与未完成的前置任务
+            if (task.parent_id) parts.push('子任务');
+            if (task.rollup && task.rollup.subtasks > 0) {
+                parts.push(`子任务 ${task.rollup.done}/${task.rollup.subtasks} (${task.rollup.percent}%)`);
+            }
+            if (task.rollup && task.rollup.open_blockers && task.rollup.open_blockers.length > 0) {
+                parts.push(`等待 ${task.rollup.open_blockers.length} 个前置任务`);
+            }
+            metaEl.textContent = parts.join(' · ');
+        }
+
         // Handle deadline display and countdown
         if (deadlineEl) {
             if (task.deadline) {
@@ -674,6 +706,8 @@ function renderTasks(tasks) {
                      }
                     deadlineEl.textContent = text;
                 }
+                // 鼠标悬停时显示会议中的原始说法
+                deadlineEl.title = task.deadline_text ? `原文: ${task.deadline_text}` : '';
             } else {
                 deadlineEl.textContent = '无截止日期';
                 deadlineEl.className = 'task-deadline text-xs text-gray-400';
@@ -727,6 +761,7 @@ function openEditDialog(task) {
     if (dialog && form && task) {
         // Populate form fields safely
         form.id.value = task.id || '';
+        form.dataset.etag = task.etag || '';
         form.title.value = task.title || '';
         form.content.value = task.content || '';
         // Format deadline for datetime-local input (YYYY-MM-DDTHH:mm)
@@ -815,17 +850,13 @@ document.addEventListener('DOMContentLoaded', () => {
             }
 
             try {
-                const response = await fetch('/task/api', { // Ensure endpoint is correct
+                const response = await fetch('/tasks', {
                     method: 'POST',
                     headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify({
-                        action: 'add', // Ensure backend expects this action
-                        task: task
-                    })
+                    body: JSON.stringify(task)
                 });
-                 if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
                 const data = await response.json();
-                if (data.status === 'success') {
+                if (response.ok) {
                     closeAddDialog();
                     loadTasks(); // Refresh list
                 } else {
@@ -853,18 +884,13 @@ document.addEventListener('DOMContentLoaded', () => {
                 const completed = e.target.checked;
 
                 try {
-                    const response = await fetch('/task/api', { // Ensure endpoint is correct
-                        method: 'POST',
-                        headers: { 'Content-Type': 'application/json' },
-                        body: JSON.stringify({
-                            action: 'update', // Ensure backend expects this action
-                            task: { id, completed } // Send only changed fields
-                        })
-                    });
-                     if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
+                    const response = await patchTask(id, item.dataset.etag, { completed });
                     const data = await response.json();
-                    if (data.status === 'success') {
+                    if (response.ok) {
                         loadTasks(); // Refresh list to show visual changes
+                    } else if (response.status === 412) {
+                        alert('任务已被其他人修改，已刷新为最新内容');
+                        loadTasks();
                     } else {
                         console.error('Failed to update task status:', data.error || 'Unknown error');
                         alert(`更新任务状态失败: ${data.error || '未知错误'}`);
@@ -890,19 +916,17 @@ document.addEventListener('DOMContentLoaded', () => {
                 if (!confirm(`确定要删除任务 "${title}" 吗？`)) return;
 
                 try {
-                    const response = await fetch('/task/api', { // Ensure endpoint is correct
-                        method: 'POST',
-                        headers: { 'Content-Type': 'application/json' },
-                        body: JSON.stringify({
-                            action: 'delete', // Ensure backend expects this action
-                            task: { id }
-                        })
+                    const response = await fetch(`/tasks/${encodeURIComponent(id)}`, {
+                        method: 'DELETE',
+                        headers: item.dataset.etag ? { 'If-Match': item.dataset.etag } : {}
                     });
-                     if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
-                    const data = await response.json();
-                    if (data.status === 'success') {
+                    if (response.ok) {
                         loadTasks(); // Refresh list
+                    } else if (response.status === 412) {
+                        alert('任务已被其他人修改，请确认后再删除');
+                        loadTasks();
                     } else {
+                         const data = await response.json();
                          console.error('Failed to delete task:', data.error || 'Unknown error');
                          alert(`删除任务失败: ${data.error || '未知错误'}`);
                     }
@@ -950,19 +974,14 @@ document.addEventListener('DOMContentLoaded', () => {
             }
 
             try {
-                const response = await fetch('/task/api', { // Ensure endpoint is correct
-                    method: 'POST',
-                    headers: { 'Content-Type': 'application/json' },
-                    body: JSON.stringify({
-                        action: 'update', // Ensure backend expects this action
-                        task: task // Send all fields for update
-                    })
-                });
-                 if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
+                const response = await patchTask(task.id, form.dataset.etag, task);
                 const data = await response.json();
-                if (data.status === 'success') {
+                if (response.ok) {
                     closeEditDialog();
                     loadTasks(); // Refresh list
+                } else if (response.status === 412) {
+                    alert('任务已被其他人修改，请关闭后重新编辑');
+                    loadTasks();
                 } else {
                      console.error('Failed to update task:', data.error || 'Unknown error');
                      alert(`更新任务失败: ${data.error || '未知错误'}`);
@@ -1007,6 +1026,9 @@ document.addEventListener('DOMContentLoaded', () => {
         }
     });
 
+    // --- Task Reminders ---
+    subscribeReminders();
+
     // --- Initial Load ---
     loadMeetings();
     loadURLState(); // loadURLState 会处理初始 Tab 和 Meeting 的加载
@@ -1079,6 +1101,18 @@ function parseTasksFromString(taskString) {
   }));
 }
 
+// 订阅任务提醒，即将到期与逾期的任务以通知显示，EventSource 断线后会带 Last-Event-ID 自动重连
+function subscribeReminders() {
+  const basePath = window.location.pathname.startsWith('/task') ? '/..' : '';
+  const source = new EventSource(`${basePath}/notifications/stream`);
+  source.addEventListener('reminder', (event) => {
+    const reminder = JSON.parse(event.data);
+    const overdue = reminder.notifications.some(n => n.kind === 'overdue');
+    showToast(reminder.text.trim().split('\n').join('；'), overdue ? 'error' : 'info');
+    loadTasks();
+  });
+}
+
 // 辅助函数：显示通知
 function showToast(message, type = 'info') {
   const toast = document.createElement('div');
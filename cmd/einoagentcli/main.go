@@ -4,16 +4,14 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"math/rand"
 	"meetingagent/cmd/einoagent/agent"
-	"meetingagent/einoagent"
 	"meetingagent/pkg/env"
-	"meetingagent/pkg/mem"
+	"meetingagent/pkg/tool/confirm"
 	"os"
 	"strconv"
 	"strings"
@@ -22,13 +20,10 @@ import (
 	"github.com/cloudwego/eino-ext/devops"
 	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/compose"
-	"github.com/cloudwego/eino/schema"
 )
 
 var id = flag.String("id", "", "conversation id")
 
-var memory = mem.GetDefaultMemory()
-
 var cbHandler callbacks.Handler
 
 func main() {
@@ -70,8 +65,8 @@ func main() {
 			return
 		}
 
-		// call RunAgent with the input
-		sr, err := agent.RunAgent(ctx, *id, input)
+		// call RunAgent with the input, destructive tool calls are confirmed on the terminal
+		sr, err := agent.RunAgent(confirm.WithConfirmer(ctx, terminalConfirmer(reader)), *id, input, compose.WithCallbacks(cbHandler))
 		if err != nil {
 			fmt.Printf("Error from RunAgent: %v", err)
 			continue
@@ -94,6 +89,22 @@ func main() {
 	}
 }
 
+// terminalConfirmer asks for confirmation on stdin, the stream is not read while a tool is running
+func terminalConfirmer(reader *bufio.Reader) confirm.Confirmer {
+	return func(ctx context.Context, req *confirm.Request) (*confirm.Decision, error) {
+		fmt.Printf("\n⚠️  %s %s %s\n   confirm? [y/N]: ", req.Tool, req.Action, string(req.Arguments))
+		answer, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer == "y" || answer == "yes" {
+			return &confirm.Decision{Approved: true}, nil
+		}
+		return &confirm.Decision{Reason: "rejected on the terminal"}, nil
+	}
+}
+
 func Init() error {
 	env.MustHasEnvs("ARK_CHAT_MODEL", "ARK_EMBEDDING_MODEL", "ARK_API_KEY")
 
@@ -140,64 +151,6 @@ func Init() error {
 	return nil
 }
 
-func RunAgent(ctx context.Context, id string, msg string) (*schema.StreamReader[*schema.Message], error) {
-	runner, err := einoagent.BuildEinoAgent(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build agent graph: %v", err)
-	}
-
-	conversation := memory.GetConversation(id, true)
-
-	userMessage := &einoagent.UserMessage{
-		ID:      id,
-		Query:   msg,
-		History: conversation.GetMessages(),
-	}
-
-	sr, err := runner.Stream(ctx, userMessage, compose.WithCallbacks(cbHandler))
-	if err != nil {
-		return nil, fmt.Errorf("failed to stream: %w", err)
-	}
-
-	srs := sr.Copy(2)
-
-	go func() {
-		// for save to memory
-		fullMsgs := make([]*schema.Message, 0)
-
-		defer func() {
-			srs[1].Close()
-
-			conversation.Append(schema.UserMessage(msg))
-
-			fullMsg, err := schema.ConcatMessages(fullMsgs)
-			if err != nil {
-				fmt.Println("error concatenating messages: ", err.Error())
-			}
-			conversation.Append(fullMsg)
-		}()
-
-	outer:
-		for {
-			select {
-			case <-ctx.Done():
-				fmt.Println("context done", ctx.Err())
-				return
-			default:
-				chunk, err := srs[1].Recv()
-				if err != nil {
-					if errors.Is(err, io.EOF) {
-						break outer
-					}
-				}
-				fullMsgs = append(fullMsgs, chunk)
-			}
-		}
-	}()
-
-	return srs[0], nil
-}
-
 type LogCallbackConfig struct {
 	Detail bool
 	Debug  bool
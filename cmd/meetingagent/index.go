@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"meetingagent/pkg/embedcache"
+	"meetingagent/pkg/env"
+	"meetingagent/rag"
+)
+
+const indexUsage = `usage: meetingagent index <subcommand> [arguments]
+
+subcommands:
+  add <files|dirs>...     index markdown meeting records, re-adding a meeting replaces its old vectors
+  list                    list indexed meetings and their chunk counts
+  delete <meeting>...     delete all vectors of the given meeting ids
+  reindex [dirs...]       clear the vector store and index dirs again (default: meetings)
+  stats                   show vector store statistics
+`
+
+func runIndex(args []string) error {
+	fs := flag.NewFlagSet("index", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, indexUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return fmt.Errorf("missing subcommand")
+	}
+
+	ctx := context.Background()
+	sub, rest := fs.Arg(0), fs.Args()[1:]
+	switch sub {
+	case "add":
+		if len(rest) == 0 {
+			return fmt.Errorf("add requires at least one file or directory")
+		}
+		env.MustHasEnvs("ARK_API_KEY", "ARK_EMBEDDING_MODEL")
+		return indexAdd(ctx, rest)
+	case "list":
+		return indexList(ctx)
+	case "delete":
+		if len(rest) == 0 {
+			return fmt.Errorf("delete requires at least one meeting id")
+		}
+		return indexDelete(ctx, rest)
+	case "reindex":
+		if len(rest) == 0 {
+			rest = []string{"meetings"}
+		}
+		env.MustHasEnvs("ARK_API_KEY", "ARK_EMBEDDING_MODEL")
+		return indexReindex(ctx, rest)
+	case "stats":
+		return indexStats(ctx)
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown subcommand: %s", sub)
+	}
+}
+
+func indexAdd(ctx context.Context, paths []string) error {
+	start := time.Now()
+	stats := &progressStats{}
+	err := rag.IndexFiles(ctx, paths, stats.print)
+	stats.summary(time.Since(start))
+	return err
+}
+
+func indexList(ctx context.Context) error {
+	meetings, err := rag.ListMeetings(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "MEETING\tCHUNKS")
+	for _, m := range meetings {
+		id := m.ID
+		if id == "" {
+			id = "(unknown)"
+		}
+		fmt.Fprintf(w, "%s\t%d\n", id, m.Chunks)
+	}
+	return w.Flush()
+}
+
+func indexDelete(ctx context.Context, meetingIDs []string) error {
+	for _, id := range meetingIDs {
+		n, err := rag.DeleteMeeting(ctx, id)
+		if err != nil {
+			return fmt.Errorf("delete %s failed: %w", id, err)
+		}
+		fmt.Printf("🗑  %s: %d chunks deleted\n", id, n)
+	}
+	return nil
+}
+
+func indexReindex(ctx context.Context, paths []string) error {
+	start := time.Now()
+	stats := &progressStats{}
+	removed, err := rag.Reindex(ctx, paths, stats.print)
+	fmt.Printf("🗑  %d old chunks removed\n", removed)
+	stats.summary(time.Since(start))
+	return err
+}
+
+func indexStats(ctx context.Context) error {
+	meetings, err := rag.ListMeetings(ctx)
+	if err != nil {
+		return err
+	}
+	chunks := 0
+	for _, m := range meetings {
+		chunks += m.Chunks
+	}
+	fmt.Printf("vector store: %s\n", env.GetString("VECTOR_STORE", "redis"))
+	fmt.Printf("meetings:     %d\n", len(meetings))
+	fmt.Printf("chunks:       %d\n", chunks)
+	return nil
+}
+
+// progressStats 汇总索引进度，ProgressFunc 的调用是互斥的，这里无需加锁
+type progressStats struct {
+	files  int
+	failed int
+	chunks int
+}
+
+func (s *progressStats) print(p rag.Progress) {
+	s.files++
+	if p.Err != nil {
+		s.failed++
+		fmt.Printf("[%d/%d] ❌ %s: %v\n", p.Done, p.Total, p.File, p.Err)
+		return
+	}
+	s.chunks += p.Chunks
+	fmt.Printf("[%d/%d] ✅ %s -> %s, %d chunks (%v)\n", p.Done, p.Total, p.File, p.MeetingID, p.Chunks, p.Elapsed.Round(time.Millisecond))
+}
+
+func (s *progressStats) summary(elapsed time.Duration) {
+	cache := embedcache.GetStats()
+	fmt.Printf("\n%d files, %d failed, %d chunks in %v (embedding cache: %d hits, %d misses)\n",
+		s.files, s.failed, s.chunks, elapsed.Round(time.Millisecond), cache.Hits, cache.Misses)
+}
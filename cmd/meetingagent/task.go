@@ -0,0 +1,124 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/tool/task"
+)
+
+const taskUsage = `usage: meetingagent task <subcommand> [arguments]
+
+subcommands:
+  migrate -to <sqlite|redis> [-from file] [-history file]
+                                             copy tasks from tasks.jsonl (default: $TASK_DIR/tasks.jsonl)
+                                             into another store, existing tasks with the same id are replaced.
+                                             the change history (default: task_history.jsonl next to -from)
+                                             is copied for tasks that have no history in the target yet
+`
+
+func runTask(args []string) error {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, taskUsage)
+		return fmt.Errorf("missing subcommand")
+	}
+
+	switch args[0] {
+	case "migrate":
+		return taskMigrate(args[1:])
+	case "-h", "--help", "help":
+		fmt.Print(taskUsage)
+		return nil
+	default:
+		fmt.Fprint(os.Stderr, taskUsage)
+		return fmt.Errorf("unknown subcommand: %s", args[0])
+	}
+}
+
+func taskMigrate(args []string) error {
+	fs := flag.NewFlagSet("task migrate", flag.ContinueOnError)
+	fs.Usage = func() { fmt.Fprint(os.Stderr, taskUsage) }
+	from := fs.String("from", filepath.Join(env.GetString("TASK_DIR", "./data/task"), "tasks.jsonl"), "tasks.jsonl to read")
+	to := fs.String("to", "", "target store, sqlite or redis")
+	historyFile := fs.String("history", "", "task_history.jsonl to read, default next to -from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *historyFile == "" {
+		*historyFile = filepath.Join(filepath.Dir(*from), "task_history.jsonl")
+	}
+	if *to != "sqlite" && *to != "redis" {
+		fs.Usage()
+		return fmt.Errorf("-to must be sqlite or redis")
+	}
+
+	if _, err := os.Stat(*from); err != nil {
+		return fmt.Errorf("failed to read %s: %w", *from, err)
+	}
+	tasks, err := task.ReadJSONL(*from)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *from, err)
+	}
+
+	store, err := task.NewStore(*to)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if err := store.Import(tasks); err != nil {
+		return fmt.Errorf("failed to import tasks: %w", err)
+	}
+	changes, err := migrateHistory(*historyFile, store)
+	if err != nil {
+		return err
+	}
+
+	deleted := 0
+	for _, t := range tasks {
+		if t.IsDeleted {
+			deleted++
+		}
+	}
+	fmt.Printf("✅ %d tasks migrated to %s (%d deleted), %d history records\n", len(tasks), *to, deleted, changes)
+	return nil
+}
+
+// migrateHistory 复制变更历史，目标中已有历史的任务跳过，重复执行不会产生重复的记录
+func migrateHistory(path string, store task.TaskStore) (int, error) {
+	changes, err := task.ReadHistoryJSONL(path, "")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	skip := make(map[string]bool)
+	var pending []*task.Change
+	for _, change := range changes {
+		if _, checked := skip[change.TaskID]; !checked {
+			existing, err := store.History(change.TaskID)
+			if err != nil {
+				return 0, fmt.Errorf("failed to read history of %s: %w", change.TaskID, err)
+			}
+			skip[change.TaskID] = len(existing) > 0
+		}
+		if !skip[change.TaskID] {
+			pending = append(pending, change)
+		}
+	}
+
+	err = store.Tx(func(tx task.TaskStore) error {
+		for _, change := range pending {
+			if err := tx.AppendHistory(change); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import history: %w", err)
+	}
+	return len(pending), nil
+}
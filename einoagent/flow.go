@@ -21,6 +21,9 @@ import (
 
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/flow/agent/react"
+
+	"meetingagent/pkg/guard"
+	"meetingagent/pkg/tool/confirm"
 )
 
 // newLambda1 component initialization function of node 'ReactAgent' in graph 'EinoAgent'
@@ -33,11 +36,27 @@ func newLambda1(ctx context.Context) (lba *compose.Lambda, err error) {
 	if err != nil {
 		return nil, err
 	}
-	config.Model = chatModelIns11
+	// 对话的时长、token、工具调用次数以及单次工具调用的超时，超过限制时以说明结束对话
+	limits, err := guard.LimitsFromEnv(config.MaxStep)
+	if err != nil {
+		return nil, err
+	}
+	config.Model = guard.WrapModel(chatModelIns11, limits)
 	tools, err := GetTools(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if tools, err = guard.WrapTools(ctx, tools, limits); err != nil {
+		return nil, err
+	}
+	// 删除、修改等操作需要用户确认后才会执行，等待确认的时间不计入工具超时
+	policy, err := confirm.PolicyFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if tools, err = confirm.Wrap(ctx, tools, policy); err != nil {
+		return nil, err
+	}
 	config.ToolsConfig.Tools = tools
 	ins, err := react.NewAgent(ctx, config)
 	if err != nil {
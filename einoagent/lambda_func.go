@@ -32,5 +32,6 @@ func newLambda2(ctx context.Context, input *UserMessage, opts ...any) (output ma
 		"content": input.Query,
 		"history": input.History,
 		"date":    time.Now().Format("2006-01-02 15:04:05"),
+		promptVar: input.Prompt,
 	}, nil
 }
@@ -0,0 +1,42 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package einoagent
+
+import (
+	"context"
+	"log"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/mem"
+)
+
+// NewMemory 创建会话记忆，cmd/einoagent 与 cmd/einoagentcli 共用，实现由 MEMORY_STORE 选择（见 mem.NewFromEnv）。
+// MEMORY_SUMMARY_ENABLED 为 true（默认）时移出窗口的消息会被折叠为摘要：
+//   - MEMORY_SUMMARY_MODEL: 生成摘要的模型，默认与对话相同
+//   - MEMORY_SUMMARY_MAX_LENGTH: 摘要的最大字数，默认 800
+func NewMemory(ctx context.Context) (mem.Memory, error) {
+	var summarizer mem.Summarizer
+	if env.GetBool("MEMORY_SUMMARY_ENABLED", true) {
+		cm, err := NewSummaryModel(ctx)
+		if err != nil {
+			log.Printf("[eino agent] WARN: summary model unavailable, older messages will be dropped: %v", err)
+		} else {
+			summarizer = mem.NewModelSummarizer(cm, env.GetInt("MEMORY_SUMMARY_MAX_LENGTH", 800))
+		}
+	}
+	return mem.NewFromEnv(summarizer)
+}
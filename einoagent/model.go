@@ -22,6 +22,8 @@ import (
 
 	"github.com/cloudwego/eino-ext/components/model/ark"
 	"github.com/cloudwego/eino/components/model"
+
+	"meetingagent/pkg/env"
 )
 
 func newChatModel(ctx context.Context) (cm model.ChatModel, err error) {
@@ -36,3 +38,12 @@ func newChatModel(ctx context.Context) (cm model.ChatModel, err error) {
 	}
 	return cm, nil
 }
+
+// NewSummaryModel 用于折叠历史对话的模型，MEMORY_SUMMARY_MODEL 未设置时与对话使用同一模型
+func NewSummaryModel(ctx context.Context) (cm model.ChatModel, err error) {
+	config := &ark.ChatModelConfig{
+		Model:  env.GetString("MEMORY_SUMMARY_MODEL", os.Getenv("ARK_CHAT_MODEL")),
+		APIKey: os.Getenv("ARK_API_KEY"),
+	}
+	return ark.NewChatModel(ctx, config)
+}
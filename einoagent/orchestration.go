@@ -25,11 +25,11 @@ import (
 
 func BuildEinoAgent(ctx context.Context) (r compose.Runnable[*UserMessage, *schema.Message], err error) {
 	const (
-		InputToQuery   = "InputToQuery"
-		ChatTemplate   = "ChatTemplate"
-		ReactAgent     = "ReactAgent"
-		RedisRetriever = "RedisRetriever"
-		InputToHistory = "InputToHistory"
+		InputToQuery    = "InputToQuery"
+		ChatTemplate    = "ChatTemplate"
+		ReactAgent      = "ReactAgent"
+		VectorRetriever = "VectorRetriever"
+		InputToHistory  = "InputToHistory"
 	)
 	g := compose.NewGraph[*UserMessage, *schema.Message]()
 	_ = g.AddLambdaNode(InputToQuery, compose.InvokableLambdaWithOption(newLambda), compose.WithNodeName("UserMessageToQuery"))
@@ -43,17 +43,17 @@ func BuildEinoAgent(ctx context.Context) (r compose.Runnable[*UserMessage, *sche
 		return nil, err
 	}
 	_ = g.AddLambdaNode(ReactAgent, reactAgentKeyOfLambda, compose.WithNodeName("ReAct Agent"))
-	redisRetrieverKeyOfRetriever, err := newRetriever(ctx)
+	vectorRetrieverKeyOfRetriever, err := newRetriever(ctx)
 	if err != nil {
 		return nil, err
 	}
-	_ = g.AddRetrieverNode(RedisRetriever, redisRetrieverKeyOfRetriever, compose.WithOutputKey("documents"))
+	_ = g.AddRetrieverNode(VectorRetriever, vectorRetrieverKeyOfRetriever, compose.WithOutputKey("documents"))
 	_ = g.AddLambdaNode(InputToHistory, compose.InvokableLambdaWithOption(newLambda2), compose.WithNodeName("UserMessageToVariables"))
 	_ = g.AddEdge(compose.START, InputToQuery)
 	_ = g.AddEdge(compose.START, InputToHistory)
 	_ = g.AddEdge(ReactAgent, compose.END)
-	_ = g.AddEdge(InputToQuery, RedisRetriever)
-	_ = g.AddEdge(RedisRetriever, ChatTemplate)
+	_ = g.AddEdge(InputToQuery, VectorRetriever)
+	_ = g.AddEdge(VectorRetriever, ChatTemplate)
 	_ = g.AddEdge(InputToHistory, ChatTemplate)
 	_ = g.AddEdge(ChatTemplate, ReactAgent)
 	r, err = g.Compile(ctx, compose.WithGraphName("EinoAgent"), compose.WithNodeTriggerMode(compose.AllPredecessor))
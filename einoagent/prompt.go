@@ -21,42 +21,12 @@ import (
 
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
-)
-
-var systemPrompt = `
-# Role: Eino Meeting Assistant
-
-## Core Competencies
-- 你获取的会议数据只会是文本格式，不会有其他非文本的数据输入
-- 识别并提取会议内容中的关键讨论点、决策和行动项
-- 将非结构化的会议数据（记录、笔记）转化为简洁、结构化的摘要
-- 高精度分析和综合自然语言内容
-- 理解常见的会议工作流程，并与协作工具（例如日历、任务跟踪器）集成
-- 可以根据会议内容以及用户的要求生成任务，并加入到任务清单中
-
-## Interaction Guidelines
-- Before responding, ensure you:
-  • 全面分析所有提供的会议材料（记录、笔记或音频），以捕捉关键主题、决策和任务
-  • 如果任何背景信息不明确或不完整（例如缺少参与者、术语模糊），向用户询问以澄清
-  • 根据用户偏好或内容复杂性调整摘要格式（例如项目符号、段落或表格）
-
-- When providing assistance:
-  • 提供简洁、条理清晰的摘要，优先考虑清晰度和可读性
-  • 突出关键元素：做出的决策、分配的行动项（包括责任人和截止日期）以及主要讨论点
-  • 引用会议中的简短相关示例或话语，以支持关键结论
-  • 提供可操作的后续步骤，例如基于这次会议提出的任务进行记录
 
+	"meetingagent/pkg/prompts"
+)
 
-- If a request exceeds your capabilities:
-  • 如果无法处理某些输入（例如未转录的音频），明确说明限制并建议解决方案（例如“仅支持文本文件输入”）
-
-- If the question is compound or complex:
-  • 将冗长或多方面的讨论分解为清晰的类别（例如主题、决策、任务）
-  • 通过交叉引用所有提供材料，确保不遗漏关键细节
-  • 一步步思考，保证答案的正确性和完整性
-
-- 如果用户要求将某事物加入到任务中，需要调用task工具，将其加入到任务中，并返回任务ID。
-`
+// promptVar InputToHistory 传给 ChatTemplate 的系统提示词模板
+const promptVar = "prompt"
 
 type ChatTemplateConfig struct {
 	FormatType schema.FormatType
@@ -69,11 +39,44 @@ func newChatTemplate(ctx context.Context) (ctp prompt.ChatTemplate, err error) {
 	config := &ChatTemplateConfig{
 		FormatType: schema.FString,
 		Templates: []schema.MessagesTemplate{
-			schema.SystemMessage(systemPrompt),
 			schema.MessagesPlaceholder("history", true),
 			schema.UserMessage("{content}"),
 		},
 	}
-	ctp = prompt.FromMessages(config.FormatType, config.Templates...)
+	ctp = &chatTemplate{messages: prompt.FromMessages(config.FormatType, config.Templates...)}
 	return ctp, nil
 }
+
+// chatTemplate 系统提示词来自 prompts 中的 agent 模板，每轮对话使用 UserMessage.Prompt 指定的版本，
+// 模板重新加载后无需重建 graph
+type chatTemplate struct {
+	messages prompt.ChatTemplate
+}
+
+func (t *chatTemplate) Format(ctx context.Context, vars map[string]any, opts ...prompt.Option) ([]*schema.Message, error) {
+	tpl, _ := vars[promptVar].(*prompts.Template)
+	if tpl == nil {
+		var err error
+		if tpl, err = AgentPrompt(); err != nil {
+			return nil, err
+		}
+	}
+	system, err := tpl.Render(ctx, vars)
+	if err != nil {
+		return nil, err
+	}
+	msgs, err := t.messages.Format(ctx, vars, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return append([]*schema.Message{schema.SystemMessage(system)}, msgs...), nil
+}
+
+// AgentPrompt 当前的 agent 系统提示词模板
+func AgentPrompt() (*prompts.Template, error) {
+	store, err := prompts.Default()
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(prompts.Agent, prompts.DefaultVariant)
+}
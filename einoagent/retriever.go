@@ -18,62 +18,63 @@ package einoagent
 
 import (
 	"context"
-	"fmt"
 	"os"
-	"strconv"
 
-	redispkg "github.com/cloudwego/eino-examples/quickstart/eino_assistant/pkg/redis"
-	"github.com/cloudwego/eino/schema"
-	redisCli "github.com/redis/go-redis/v9"
-
-	"github.com/cloudwego/eino-ext/components/retriever/redis"
 	"github.com/cloudwego/eino/components/retriever"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/vectorstore"
 )
 
-// newRetriever component initialization function of node 'RedisRetriever' in graph 'EinoAgent'
+// RetrieverConfig 检索参数，默认值来自环境变量，单次对话可通过 retriever.WithTopK、
+// retriever.WithScoreThreshold 与 WithRetrievalFilter 覆盖
+type RetrieverConfig struct {
+	// TopK 返回的最大文档数，RETRIEVER_TOP_K，默认 8
+	TopK int
+	// MinScore 最低相似度（1 - cosine distance），低于该值的文档会被丢弃，RETRIEVER_MIN_SCORE，默认 0
+	MinScore float64
+	// Filter 部署级别的过滤表达式（Redis 为 RediSearch 查询），会与单次请求的过滤条件取交集，RETRIEVER_FILTER
+	Filter string
+}
+
+func retrieverConfigFromEnv() *RetrieverConfig {
+	return &RetrieverConfig{
+		TopK:     env.GetInt("RETRIEVER_TOP_K", 8),
+		MinScore: env.GetFloat("RETRIEVER_MIN_SCORE", 0),
+		Filter:   os.Getenv("RETRIEVER_FILTER"),
+	}
+}
+
+// RetrievalFilter 单次检索的元数据过滤条件，零值字段表示不过滤
+type RetrievalFilter = vectorstore.Filter
+
+// WithRetrievalFilter 为单次检索指定会议、发言人与日期范围过滤条件
+func WithRetrievalFilter(filter *RetrievalFilter) retriever.Option {
+	return vectorstore.WithFilter(filter)
+}
+
+// newRetriever component initialization function of node 'VectorRetriever' in graph 'EinoAgent'
 func newRetriever(ctx context.Context) (rtr retriever.Retriever, err error) {
 	// TODO Modify component configuration here.
-	redisAddr := os.Getenv("REDIS_ADDR")
-	redisClient := redisCli.NewClient(&redisCli.Options{
-		Addr:     redisAddr,
-		Protocol: 2,
-	})
-	config := &redis.RetrieverConfig{
-		Client:       redisClient,
-		Index:        fmt.Sprintf("%s%s", redispkg.RedisPrefix, redispkg.IndexName),
-		Dialect:      2,
-		ReturnFields: []string{redispkg.ContentField, redispkg.MetadataField, redispkg.DistanceField},
-		TopK:         8,
-		VectorField:  redispkg.VectorField,
-		DocumentConverter: func(ctx context.Context, doc redisCli.Document) (*schema.Document, error) {
-			resp := &schema.Document{
-				ID:       doc.ID,
-				Content:  "",
-				MetaData: map[string]any{},
-			}
-			for field, val := range doc.Fields {
-				if field == redispkg.ContentField {
-					resp.Content = val
-				} else if field == redispkg.MetadataField {
-					resp.MetaData[field] = val
-				} else if field == redispkg.DistanceField {
-					distance, err := strconv.ParseFloat(val, 64)
-					if err != nil {
-						continue
-					}
-					resp.WithScore(1 - distance)
-				}
-			}
-
-			return resp, nil
-		},
+	store, err := vectorstore.Default(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rc := retrieverConfigFromEnv()
+	config := &vectorstore.RetrieverConfig{
+		Store:    store,
+		TopK:     rc.TopK,
+		MinScore: rc.MinScore,
+	}
+	if rc.Filter != "" {
+		config.Filter = &vectorstore.Filter{Raw: rc.Filter}
 	}
 	embeddingIns11, err := newEmbedding(ctx)
 	if err != nil {
 		return nil, err
 	}
 	config.Embedding = embeddingIns11
-	rtr, err = redis.NewRetriever(ctx, config)
+	rtr, err = vectorstore.NewRetriever(ctx, config)
 	if err != nil {
 		return nil, err
 	}
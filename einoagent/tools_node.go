@@ -19,32 +19,40 @@ package einoagent
 import (
 	"context"
 
-	"github.com/cloudwego/eino-examples/quickstart/eino_assistant/pkg/tool/einotool"
-	"github.com/cloudwego/eino-examples/quickstart/eino_assistant/pkg/tool/task"
 	"github.com/cloudwego/eino/components/tool"
+
+	"meetingagent/pkg/tool/deadline"
+	"meetingagent/pkg/tool/meeting"
+	"meetingagent/pkg/tool/task"
 )
 
 func GetTools(ctx context.Context) ([]tool.BaseTool, error) {
-	einoAssistantTool, err := NewEinoAssistantTool(ctx)
+	toolTask, err := NewTaskTool(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	toolTask, err := NewTaskTool(ctx)
+	meetingTools, err := NewMeetingTools(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	return []tool.BaseTool{
-		einoAssistantTool,
-		toolTask,
-	}, nil
-}
+	toolDeadline, err := NewDeadlineTool(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-func NewEinoAssistantTool(ctx context.Context) (tn tool.BaseTool, err error) {
-	return einotool.NewEinoAssistantTool(ctx, nil)
+	return append([]tool.BaseTool{toolTask, toolDeadline}, meetingTools...), nil
 }
 
 func NewTaskTool(ctx context.Context) (tn tool.BaseTool, err error) {
 	return task.NewTaskTool(ctx, nil)
 }
+
+func NewDeadlineTool(ctx context.Context) (tn tool.BaseTool, err error) {
+	return deadline.NewTool(ctx, nil)
+}
+
+func NewMeetingTools(ctx context.Context) (tools []tool.BaseTool, err error) {
+	return meeting.NewTools(ctx, nil)
+}
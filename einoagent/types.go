@@ -16,10 +16,16 @@
 
 package einoagent
 
-import "github.com/cloudwego/eino/schema"
+import (
+	"github.com/cloudwego/eino/schema"
+
+	"meetingagent/pkg/prompts"
+)
 
 type UserMessage struct {
 	ID      string            `json:"id"`
 	Query   string            `json:"query"`
 	History []*schema.Message `json:"history"`
+	// Prompt 本轮使用的系统提示词模板，为空时使用当前的 agent 模板
+	Prompt *prompts.Template `json:"-"`
 }
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+
+	"meetingagent/cmd/einoagent/agent"
+	"meetingagent/pkg/tool/confirm"
+)
+
+type agentFunc func(ctx context.Context, opts ...compose.Option) (*schema.StreamReader[*schema.Message], error)
+
+// runChat 登记一次对话并运行 agent，将事件以 SSE 推送给客户端。
+// 同一会话已有进行中的对话时返回 409
+func runChat(ctx context.Context, c *app.RequestContext, sessionID string, run agentFunc, opts ...compose.Option) {
+	chat, err := newChatRun(ctx, sessionID)
+	if err != nil {
+		c.JSON(consts.StatusConflict, utils.H{"error": err.Error()})
+		return
+	}
+	opts = append(opts, compose.WithCallbacks(chat.callbacks()))
+
+	sr, err := run(confirm.WithConfirmer(chat.ctx, chat.confirm), opts...)
+	if err != nil {
+		log.Printf("[Chat] Error running agent: %v\n", err)
+		chat.finish()
+		status := consts.StatusInternalServerError
+		if errors.Is(err, agent.ErrNothingToRegenerate) {
+			status = consts.StatusNotFound
+		}
+		c.JSON(status, map[string]string{
+			"status": "error",
+			"error":  err.Error(),
+		})
+		return
+	}
+	go chat.consume(sr)
+
+	streamChatRun(ctx, c, chat, 0)
+	log.Printf("[Chat] Finished chat with sessionID: %s\n", sessionID)
+}
+
+// StopChat 停止会话中进行中的对话，POST /chat/:session/stop。
+// 已生成的部分回答会保留在会话中，事件流以 stopped 为 true 的 done 结束
+func StopChat(ctx context.Context, c *app.RequestContext) {
+	sessionID := c.Param("session")
+	run := activeChatRun(sessionID)
+	if run == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "no running chat in this session"})
+		return
+	}
+	run.stop()
+	log.Printf("[Chat] Stopped chat %s of sessionID: %s\n", run.id, sessionID)
+	c.JSON(consts.StatusOK, utils.H{"run_id": run.id, "stopped": true})
+}
+
+// RegenerateChat 以会话中最后一轮的问题重新回答并替换这一轮，POST /chat/:session/regenerate。
+// 响应与 GET /chat 相同，支持相同的检索参数
+func RegenerateChat(ctx context.Context, c *app.RequestContext) {
+	sessionID := c.Param("session")
+
+	opts, err := retrievalOptions(c)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+
+	runChat(ctx, c, sessionID, func(ctx context.Context, opts ...compose.Option) (*schema.StreamReader[*schema.Message], error) {
+		return agent.Regenerate(ctx, sessionID, opts...)
+	}, opts...)
+}
+
+type confirmRequest struct {
+	ConfirmationID string `json:"confirmation_id"`
+	Approved       bool   `json:"approved"`
+	Reason         string `json:"reason"`
+}
+
+// ConfirmChat 批准或拒绝等待确认的工具调用，POST /chat/:session/confirm。
+// 批准后工具调用继续执行，拒绝时工具不会执行，agent 会收到拒绝的结果
+func ConfirmChat(ctx context.Context, c *app.RequestContext) {
+	sessionID := c.Param("session")
+
+	var req confirmRequest
+	if err := c.BindJSON(&req); err != nil || req.ConfirmationID == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "confirmation_id is required"})
+		return
+	}
+
+	run := activeChatRun(sessionID)
+	if run == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "no running chat in this session"})
+		return
+	}
+	if !run.resolve(req.ConfirmationID, &confirm.Decision{Approved: req.Approved, Reason: req.Reason}) {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "confirmation not found or already resolved"})
+		return
+	}
+	log.Printf("[Chat] Confirmation %s resolved, approved: %v, sessionID: %s\n", req.ConfirmationID, req.Approved, sessionID)
+	c.JSON(consts.StatusOK, utils.H{"confirmation_id": req.ConfirmationID, "approved": req.Approved})
+}
@@ -0,0 +1,484 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	ucb "github.com/cloudwego/eino/utils/callbacks"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/google/uuid"
+	"github.com/hertz-contrib/sse"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/guard"
+	"meetingagent/pkg/tool/confirm"
+	"meetingagent/pkg/vectorstore"
+)
+
+// 对话 SSE 的事件名
+const (
+	EventToken      = "token"
+	EventToolCall   = "tool_call"
+	EventToolResult = "tool_result"
+	EventRetrieval  = "retrieval"
+	EventCitation   = "citation"
+	EventUsage      = "usage"
+	EventDone       = "done"
+	EventError      = "error"
+
+	EventConfirmationRequired = "confirmation_required"
+	EventConfirmationResolved = "confirmation_resolved"
+)
+
+// previewRunes retrieval 事件中文档内容的最大长度
+const previewRunes = 200
+
+type chatEvent struct {
+	seq  int
+	name string
+	data []byte
+}
+
+// chatRun 一次对话产生的全部事件。agent 的输出先写入 chatRun 再推送给客户端，
+// 客户端断线后带 Last-Event-ID 重连即可从断点继续接收，不会重新运行 agent
+type chatRun struct {
+	id        string
+	sessionID string
+	ctx       context.Context
+	cancel    context.CancelFunc
+	stopped   atomic.Bool
+
+	mu       sync.Mutex
+	events   []chatEvent
+	done     bool
+	finished time.Time
+	notify   chan struct{}
+	// exited 在对话结束时关闭，此时这一轮已经写入会话
+	exited chan struct{}
+	// confirmations 等待用户确认的工具调用，key 为确认 ID
+	confirmations map[string]chan *confirm.Decision
+
+	// 以下字段只在回调与 consume 中使用
+	docs      []*schema.Document
+	usage     model.TokenUsage
+	modelRuns int
+	pending   sync.WaitGroup
+}
+
+var (
+	chatRunsMu sync.Mutex
+	chatRuns   = make(map[string]*chatRun)
+	// activeRuns 每个会话进行中的对话，同一会话同时只能有一个
+	activeRuns = make(map[string]*chatRun)
+)
+
+// ErrRunInProgress 会话中已有进行中的对话
+var ErrRunInProgress = errors.New("a chat is already running in this session")
+
+// newChatRun 创建并登记一次对话，同时清理结束超过 CHAT_RUN_TTL（默认 5m）的对话。
+// 对话的 ctx 与请求无关，只会被 stop 取消，客户端断开后对话继续运行
+func newChatRun(ctx context.Context, sessionID string) (*chatRun, error) {
+	run := &chatRun{
+		id:            uuid.NewString(),
+		sessionID:     sessionID,
+		notify:        make(chan struct{}),
+		exited:        make(chan struct{}),
+		confirmations: make(map[string]chan *confirm.Decision),
+	}
+
+	ttl := env.GetDuration("CHAT_RUN_TTL", 5*time.Minute)
+	chatRunsMu.Lock()
+	defer chatRunsMu.Unlock()
+	if active, ok := activeRuns[sessionID]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrRunInProgress, active.id)
+	}
+	for id, r := range chatRuns {
+		r.mu.Lock()
+		expired := r.done && time.Since(r.finished) > ttl
+		r.mu.Unlock()
+		if expired {
+			delete(chatRuns, id)
+		}
+	}
+	run.ctx, run.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	chatRuns[run.id] = run
+	activeRuns[sessionID] = run
+	return run, nil
+}
+
+// activeChatRun 返回会话中进行中的对话
+func activeChatRun(sessionID string) *chatRun {
+	chatRunsMu.Lock()
+	defer chatRunsMu.Unlock()
+	return activeRuns[sessionID]
+}
+
+// stop 取消对话，取消会传递到进行中的模型调用与工具调用
+func (r *chatRun) stop() {
+	r.stopped.Store(true)
+	r.cancel()
+}
+
+func getChatRun(id string) *chatRun {
+	chatRunsMu.Lock()
+	defer chatRunsMu.Unlock()
+	return chatRuns[id]
+}
+
+// eventID 事件 ID 为 <run id>-<序号>，序号从 1 开始
+func (r *chatRun) eventID(seq int) string {
+	return r.id + "-" + strconv.Itoa(seq)
+}
+
+// parseEventID 解析 Last-Event-ID，返回对话 ID 与最后收到的事件序号
+func parseEventID(id string) (runID string, seq int, err error) {
+	i := strings.LastIndex(id, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("invalid event id: %s", id)
+	}
+	seq, err = strconv.Atoi(id[i+1:])
+	if err != nil || seq < 0 {
+		return "", 0, fmt.Errorf("invalid event id: %s", id)
+	}
+	return id[:i], seq, nil
+}
+
+func (r *chatRun) emit(name string, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[Chat] Error marshalling %s event: %v\n", name, err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.events = append(r.events, chatEvent{seq: len(r.events) + 1, name: name, data: data})
+	close(r.notify)
+	r.notify = make(chan struct{})
+}
+
+func (r *chatRun) finish() {
+	chatRunsMu.Lock()
+	if activeRuns[r.sessionID] == r {
+		delete(activeRuns, r.sessionID)
+	}
+	chatRunsMu.Unlock()
+	r.cancel()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	r.done = true
+	r.finished = time.Now()
+	close(r.notify)
+	close(r.exited)
+}
+
+// since 返回序号大于 seq 的事件；对话未结束时返回的 channel 会在有新事件时关闭
+func (r *chatRun) since(seq int) ([]chatEvent, bool, <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if seq > len(r.events) {
+		seq = len(r.events)
+	}
+	return r.events[seq:], r.done, r.notify
+}
+
+// callbacks 将检索、工具调用与模型用量转换为事件，通过 compose.WithCallbacks 注入 graph，
+// 对 ReAct Agent 内部的节点同样生效
+func (r *chatRun) callbacks() callbacks.Handler {
+	return ucb.NewHandlerHelper().
+		Retriever(&ucb.RetrieverCallbackHandler{
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *retriever.CallbackOutput) context.Context {
+				r.onRetrieval(output.Docs)
+				return ctx
+			},
+		}).
+		Tool(&ucb.ToolCallbackHandler{
+			OnStart: func(ctx context.Context, info *callbacks.RunInfo, input *tool.CallbackInput) context.Context {
+				r.emit(EventToolCall, map[string]any{
+					"id":        compose.GetToolCallID(ctx),
+					"name":      info.Name,
+					"arguments": json.RawMessage(validJSON(input.ArgumentsInJSON)),
+				})
+				return ctx
+			},
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *tool.CallbackOutput) context.Context {
+				r.emit(EventToolResult, map[string]any{
+					"id":     compose.GetToolCallID(ctx),
+					"name":   info.Name,
+					"result": json.RawMessage(validJSON(output.Response)),
+				})
+				return ctx
+			},
+			OnError: func(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+				r.emit(EventToolResult, map[string]any{
+					"id":    compose.GetToolCallID(ctx),
+					"name":  info.Name,
+					"error": err.Error(),
+				})
+				return ctx
+			},
+		}).
+		ChatModel(&ucb.ModelCallbackHandler{
+			OnEnd: func(ctx context.Context, info *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+				r.addUsage(output.TokenUsage)
+				return ctx
+			},
+			OnEndWithStreamOutput: func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+				r.pending.Add(1)
+				go func() {
+					defer r.pending.Done()
+					defer output.Close()
+					// 流式输出的用量在最后一个 chunk 中
+					var usage *model.TokenUsage
+					for {
+						chunk, err := output.Recv()
+						if err != nil {
+							break
+						}
+						if chunk.TokenUsage != nil {
+							usage = chunk.TokenUsage
+						}
+					}
+					r.addUsage(usage)
+				}()
+				return ctx
+			},
+		}).
+		Handler()
+}
+
+func (r *chatRun) onRetrieval(docs []*schema.Document) {
+	type retrievedDoc struct {
+		ID        string  `json:"id"`
+		Score     float64 `json:"score"`
+		MeetingID string  `json:"meeting_id,omitempty"`
+		Content   string  `json:"content"`
+	}
+	payload := make([]retrievedDoc, 0, len(docs))
+	for _, doc := range docs {
+		meetingID, _ := doc.MetaData[vectorstore.MetaMeetingID].(string)
+		content := []rune(doc.Content)
+		if len(content) > previewRunes {
+			content = append(content[:previewRunes], '…')
+		}
+		payload = append(payload, retrievedDoc{
+			ID:        doc.ID,
+			Score:     doc.Score(),
+			MeetingID: meetingID,
+			Content:   string(content),
+		})
+	}
+
+	r.mu.Lock()
+	r.docs = append(r.docs, docs...)
+	r.mu.Unlock()
+	r.emit(EventRetrieval, map[string]any{"documents": payload})
+}
+
+func (r *chatRun) addUsage(usage *model.TokenUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modelRuns++
+	if usage == nil {
+		return
+	}
+	r.usage.PromptTokens += usage.PromptTokens
+	r.usage.CompletionTokens += usage.CompletionTokens
+	r.usage.TotalTokens += usage.TotalTokens
+}
+
+// consume 读取 agent 的输出并写入事件，结束后依次发送 citation、usage 与 done；
+// 出错时发送 error 并结束，被 stop 时 done 中 stopped 为 true
+func (r *chatRun) consume(sr *schema.StreamReader[*schema.Message]) {
+	defer r.finish()
+	defer sr.Close()
+
+	for {
+		msg, err := sr.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil && r.stopped.Load() {
+			break
+		}
+		if err != nil {
+			log.Printf("[Chat] Error receiving message: %v\n", err)
+			r.emit(EventError, map[string]any{"error": err.Error()})
+			return
+		}
+		if msg.Content == "" {
+			continue
+		}
+		r.emit(EventToken, map[string]any{
+			"message":   msg.Content,
+			"timestamp": time.Now().UTC().Format(time.RFC3339),
+			"sender":    "Agent",
+		})
+	}
+
+	r.pending.Wait()
+	for _, citation := range r.citations() {
+		r.emit(EventCitation, citation)
+	}
+	r.mu.Lock()
+	usage, modelRuns := r.usage, r.modelRuns
+	r.mu.Unlock()
+	r.emit(EventUsage, map[string]any{
+		"prompt_tokens":     usage.PromptTokens,
+		"completion_tokens": usage.CompletionTokens,
+		"total_tokens":      usage.TotalTokens,
+		"model_calls":       modelRuns,
+	})
+	r.emit(EventDone, map[string]any{"run_id": r.id, "stopped": r.stopped.Load()})
+}
+
+type citation struct {
+	MeetingID   string   `json:"meeting_id"`
+	Score       float64  `json:"score"`
+	DocumentIDs []string `json:"document_ids"`
+}
+
+// citations 按会议汇总检索到的文档，按最高相似度倒序
+func (r *chatRun) citations() []*citation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	byMeeting := make(map[string]*citation)
+	var result []*citation
+	for _, doc := range r.docs {
+		meetingID, _ := doc.MetaData[vectorstore.MetaMeetingID].(string)
+		if meetingID == "" {
+			continue
+		}
+		c, ok := byMeeting[meetingID]
+		if !ok {
+			c = &citation{MeetingID: meetingID}
+			byMeeting[meetingID] = c
+			result = append(result, c)
+		}
+		c.DocumentIDs = append(c.DocumentIDs, doc.ID)
+		if doc.Score() > c.Score {
+			c.Score = doc.Score()
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	return result
+}
+
+// streamChatRun 将序号大于 after 的事件推送给客户端，直到对话结束或客户端断开
+func streamChatRun(ctx context.Context, c *app.RequestContext, run *chatRun, after int) {
+	s := sse.NewStream(c)
+	defer c.Flush()
+
+	for {
+		events, done, notify := run.since(after)
+		for _, e := range events {
+			err := s.Publish(&sse.Event{
+				ID:    run.eventID(e.seq),
+				Event: e.name,
+				Data:  e.data,
+			})
+			if err != nil {
+				log.Printf("[Chat] Error publishing message: %v\n", err)
+				return
+			}
+			after = e.seq
+		}
+		if done && len(events) == 0 {
+			return
+		}
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			log.Printf("[Chat] Context done for chat sessionID: %s\n", run.sessionID)
+			return
+		}
+	}
+}
+
+// confirm 实现 confirm.Confirmer：发送 confirmation_required 事件后暂停工具调用，
+// 直到用户通过 POST /chat/:session/confirm 做出决定。超过 CONFIRMATION_TIMEOUT（默认 5m）
+// 视为拒绝，对话被 stop 时返回 ctx 的错误
+func (r *chatRun) confirm(ctx context.Context, req *confirm.Request) (*confirm.Decision, error) {
+	ch := make(chan *confirm.Decision, 1)
+	r.mu.Lock()
+	r.confirmations[req.ID] = ch
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.confirmations, req.ID)
+		r.mu.Unlock()
+	}()
+
+	// 等待确认的时间不计入对话时长的限制
+	defer guard.Pause(ctx)()
+
+	r.emit(EventConfirmationRequired, req)
+	log.Printf("[Chat] Waiting for confirmation %s of tool %s, sessionID: %s\n", req.ID, req.Tool, r.sessionID)
+
+	timer := time.NewTimer(env.GetDuration("CONFIRMATION_TIMEOUT", 5*time.Minute))
+	defer timer.Stop()
+
+	var decision *confirm.Decision
+	select {
+	case decision = <-ch:
+	case <-timer.C:
+		decision = &confirm.Decision{Reason: "confirmation timed out"}
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	r.emit(EventConfirmationResolved, map[string]any{
+		"id":       req.ID,
+		"approved": decision.Approved,
+		"reason":   decision.Reason,
+	})
+	return decision, nil
+}
+
+// resolve 将用户的决定交给等待中的工具调用，没有对应的确认时返回 false
+func (r *chatRun) resolve(id string, decision *confirm.Decision) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch, ok := r.confirmations[id]
+	if !ok {
+		return false
+	}
+	delete(r.confirmations, id)
+	ch <- decision
+	return true
+}
+
+// validJSON 工具的参数与返回值通常是 JSON，不是时按字符串输出
+func validJSON(s string) []byte {
+	if json.Valid([]byte(s)) {
+		return []byte(s)
+	}
+	b, _ := json.Marshal(s)
+	return b
+}
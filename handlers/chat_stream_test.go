@@ -0,0 +1,41 @@
+package handlers
+
+import "testing"
+
+func TestParseEventID(t *testing.T) {
+	tests := []struct {
+		id      string
+		runID   string
+		seq     int
+		wantErr bool
+	}{
+		// 对话 ID 是 UUID，本身带有 "-"
+		{id: "6f1c2f9e-8a4b-4c1d-9e2f-0a1b2c3d4e5f-12", runID: "6f1c2f9e-8a4b-4c1d-9e2f-0a1b2c3d4e5f", seq: 12},
+		{id: "run-0", runID: "run", seq: 0},
+		{id: "run-+", wantErr: true},
+		{id: "run-", wantErr: true},
+		{id: "run-x", wantErr: true},
+		{id: "-3", wantErr: true},
+		{id: "12", wantErr: true},
+		{id: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			runID, seq, err := parseEventID(tt.id)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("parseEventID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			}
+			if runID != tt.runID || seq != tt.seq {
+				t.Errorf("parseEventID(%q) = %q, %d, want %q, %d", tt.id, runID, seq, tt.runID, tt.seq)
+			}
+		})
+	}
+}
+
+func TestEventIDRoundTrip(t *testing.T) {
+	run := &chatRun{id: "6f1c2f9e-8a4b-4c1d-9e2f-0a1b2c3d4e5f"}
+	runID, seq, err := parseEventID(run.eventID(7))
+	if err != nil || runID != run.id || seq != 7 {
+		t.Errorf("parseEventID(eventID(7)) = %q, %d, %v", runID, seq, err)
+	}
+}
@@ -3,20 +3,21 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"path/filepath"
 	"time"
 
 	"meetingagent/cmd/einoagent/agent"
+	"meetingagent/knowledgeindexing"
 	"meetingagent/models"
 	"meetingagent/pkg/env"
 	"meetingagent/rag"
 	"meetingagent/redis"
 
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/common/utils"
 	"github.com/cloudwego/hertz/pkg/protocol/consts"
@@ -27,48 +28,6 @@ import (
 	"github.com/volcengine/volcengine-go-sdk/volcengine"
 )
 
-const prompt string = `
-# Role: 会议总结和任务/Todo生成助手
-
-## Core Competencies
-- 你获取的会议数据只会是文本格式，不会有其他非文本的数据输入
-- 识别并提取会议内容中的关键讨论点、决策和行动项
-- 将非结构化的会议数据（记录、笔记）转化为简洁、结构化的摘要
-- 高精度分析和综合自然语言内容
-- 生成的内容的标题应该简洁明了，且为一级标题
-- 理解常见的会议工作流程，并与协作工具（例如日历、任务跟踪器）集成
-
-## Interaction Guidelines
-- Before responding, ensure you:
-  • 全面分析所有提供的会议材料（记录、笔记或音频），以捕捉关键主题、决策和任务
-  • 如果任何背景信息不明确或不完整（例如缺少参与者、术语模糊），向用户询问以澄清
-  • 根据用户偏好或内容复杂性调整摘要格式（例如项目符号、段落或表格）
-
-- When providing assistance:
-  • 提供简洁、条理清晰的摘要，优先考虑清晰度和可读性
-  • 突出会议主要的讨论点以及结果
-  • 突出关键元素：做出的决策、分配的行动项（包括责任人和截止日期），决策和分配的行动项最好能按表格展示
-  • 引用会议中的简短相关示例或话语，以支持关键结论
-  • 如果生成的内容能够细分为多点，请将其分解为多个要点
-  • 提供可操作的后续步骤，例如基于这次会议提出的任务进行记录
-
-
-- If a request exceeds your capabilities:
-  • 如果无法处理某些输入（例如未转录的音频），明确说明限制并建议解决方案（例如“仅支持文本文件输入”）
-
-- If the question is compound or complex:
-  • 将冗长或多方面的讨论分解为清晰的类别（例如主题、决策、任务）
-  • 通过交叉引用所有提供材料，确保不遗漏关键细节
-  • 一步步思考，保证答案的正确性和完整性
-
-## Context Information
-- 当前日期: {meetingDate}
-- 会议材料: |-
-==== meeting_doc start ====
-  {meetingTranscript}
-==== meeting_doc end ====
-`
-
 func init() {
 	// 加载.env文件
 	err := godotenv.Load()
@@ -93,8 +52,8 @@ func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 
 	//fmt.Printf("create meeting: %s\n", string(jsonBody))
 
-	timestamp := time.Now().Format("20060102_150405")
-	markdownFilePath := filepath.Join("meetings", fmt.Sprintf("%s.md", timestamp))
+	createdAt := time.Now()
+	markdownFilePath := filepath.Join("meetings", knowledgeindexing.MeetingFileName(createdAt))
 	if err := os.MkdirAll("meetings", 0755); err != nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to create meetings directory: " + err.Error()})
 		return
@@ -105,28 +64,49 @@ func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	rag.IndexMarkdownFiles(ctx, "meetings")
+	// 只索引新会议，索引失败不影响摘要生成，可稍后通过 meetingagent index add 补齐
+	if err := rag.IndexFiles(ctx, []string{markdownFilePath}, rag.LogProgress); err != nil {
+		log.Printf("Error indexing meeting %s: %v", markdownFilePath, err)
+	}
 	// TODO: Implement actual meeting creation logic
 	response := models.PostMeetingResponse{
-		ID: "meeting_" + time.Now().Format("20060102150405"),
+		ID: knowledgeindexing.MeetingIDFromPath(markdownFilePath),
 	}
 
-	meetingDate := time.Now().Format("2006-01-02")
-	meetingTranscript := string(jsonBody)
-	prompt := fmt.Sprintf(prompt, meetingDate, meetingTranscript)
+	// 按会议类型选择总结模板，没有对应模板时使用默认模板
+	meetingType := c.Query("meeting_type")
+	if v, ok := reqBody["meeting_type"].(string); ok && meetingType == "" {
+		meetingType = v
+	}
+	tpl, err := summaryPrompt(meetingType)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+	prompt, err := tpl.Render(ctx, map[string]any{
+		"meetingDate":       createdAt.Format("2006-01-02"),
+		"meetingTranscript": string(jsonBody),
+	})
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
 	// 调用LLM生成总结
 	summary, err := LLM(prompt)
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "生成会议总结失败"})
 		return
 	}
+	log.Printf("meeting %s summarized with prompt %s", response.ID, tpl.Ref())
 
 	// 构建完整数据
 	meetingData := models.Meeting{
-		ID:        response.ID,
-		Content:   reqBody,
-		Summary:   summary,
-		CreatedAt: time.Now().Format(time.RFC3339),
+		ID:            response.ID,
+		Content:       reqBody,
+		Summary:       summary,
+		CreatedAt:     createdAt.Format(time.RFC3339),
+		MeetingType:   meetingType,
+		PromptVersion: tpl.Ref(),
 	}
 
 	completeData, err := json.Marshal(meetingData)
@@ -216,6 +196,9 @@ func GetMeetingSummary(ctx context.Context, c *app.RequestContext) {
 		"summary":    meetingData["summary"],
 		"created_at": meetingData["created_at"],
 	}
+	if v, ok := meetingData["prompt_version"]; ok {
+		response["prompt_version"] = v
+	}
 	log.Printf("读取redis的会议内容")
 	c.JSON(consts.StatusOK, response)
 }
@@ -276,7 +259,8 @@ func LLM(prompt string) (string, error) {
 	return *resp.Choices[0].Message.Content.StringValue, nil
 }
 
-// HandleChat handles the SSE chat session
+// HandleChat handles the SSE chat session.
+// 每个事件都带有 ID，客户端重连时通过 Last-Event-ID 头（或 last_event_id 参数）从断点继续接收
 func HandleChat(ctx context.Context, c *app.RequestContext) {
 	meetingID := c.Query("meeting_id")
 	sessionID := c.Query("session_id")
@@ -290,72 +274,44 @@ func HandleChat(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
+	lastEventID := sse.GetLastEventID(c)
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	if lastEventID != "" {
+		runID, seq, err := parseEventID(lastEventID)
+		if err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+			return
+		}
+		run := getChatRun(runID)
+		if run == nil || run.sessionID != sessionID {
+			c.JSON(consts.StatusGone, utils.H{"error": "chat run not found or expired"})
+			return
+		}
+		log.Printf("[Chat] Resume chat sessionID: %s from event %d\n", sessionID, seq)
+		streamChatRun(ctx, c, run, seq)
+		return
+	}
+
 	if message == "" {
 		c.JSON(consts.StatusBadRequest, utils.H{"error": "message is required"})
 		return
 	}
 
-	fmt.Printf("meetingID: %s, sessionID: %s, message: %s\n", meetingID, sessionID, message)
-
-	sr, err := agent.RunAgent(ctx, sessionID, message)
-
+	opts, err := retrievalOptions(c)
 	if err != nil {
-		log.Printf("[Chat] Error running agent: %v\n", err)
-		c.JSON(consts.StatusInternalServerError, map[string]string{
-			"status": "error",
-			"error":  err.Error(),
-		})
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
-	// Create SSE stream
-	s := sse.NewStream(c)
-	defer func() {
-		sr.Close()
-		c.Flush()
-
-		log.Printf("[Chat] Finished chat with sessionID: %s\n", sessionID) // Use sessionID for logging consistency
-	}()
-
-outer:
-	for {
-		select {
-		case <-ctx.Done():
-			log.Printf("[Chat] Context done for chat sessionID: %s\n", sessionID) // Use sessionID for logging consistency
-			return
-		default:
-			msg, err := sr.Recv()
-			if errors.Is(err, io.EOF) {
-				log.Printf("[Chat] EOF received for chat sessionID: %s\n", sessionID) // Use sessionID for logging consistency
-				break outer
-			}
-			if err != nil {
-				log.Printf("[Chat] Error receiving message: %v\n", err)
-				break outer
-			}
-
-			// 构造符合API文档格式的响应数据
-			responseData := map[string]interface{}{
-				"data": map[string]interface{}{
-					"message":   msg.Content,
-					"timestamp": time.Now().UTC().Format(time.RFC3339), // 使用当前UTC时间
-					"sender":    "Agent",                               // 发送者标识为Agent
-				},
-			}
-
-			// 将响应数据序列化为JSON
-			jsonData, err := json.Marshal(responseData)
-			if err != nil {
-				log.Printf("[Chat] Error marshalling JSON: %v\n", err)
-				continue // 如果序列化失败，跳过这条消息
-			}
-			// 发布JSON数据
-			err = s.Publish(&sse.Event{
-				Data: jsonData,
-			})
-			if err != nil {
-				log.Printf("[Chat] Error publishing message: %v\n", err)
-				break outer
-			}
-		}
+
+	fmt.Printf("meetingID: %s, sessionID: %s, message: %s\n", meetingID, sessionID, message)
+
+	if err := agent.BindMeeting(sessionID, meetingID); err != nil {
+		log.Printf("[Chat] Error binding session %s to meeting %s: %v\n", sessionID, meetingID, err)
 	}
+
+	runChat(ctx, c, sessionID, func(ctx context.Context, opts ...compose.Option) (*schema.StreamReader[*schema.Message], error) {
+		return agent.RunAgent(ctx, sessionID, message, opts...)
+	}, opts...)
 }
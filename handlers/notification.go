@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/hertz-contrib/sse"
+
+	"meetingagent/pkg/reminder"
+)
+
+// EventReminder 站内通知流中的提醒事件
+const EventReminder = "reminder"
+
+type ListNotificationsResponse struct {
+	Notifications []*reminder.FeedEvent `json:"notifications"`
+}
+
+func notificationFeed(c *app.RequestContext) (*reminder.Feed, bool) {
+	scheduler, err := reminder.Default()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return nil, false
+	}
+	return scheduler.Feed(), true
+}
+
+// ListNotifications 最近的任务提醒，GET /notifications?assignee=&after=
+func ListNotifications(ctx context.Context, c *app.RequestContext) {
+	feed, ok := notificationFeed(c)
+	if !ok {
+		return
+	}
+	after, _ := strconv.ParseInt(c.Query("after"), 10, 64)
+	events, _ := feed.Since(after, c.Query("assignee"))
+	if events == nil {
+		events = make([]*reminder.FeedEvent, 0)
+	}
+	c.JSON(consts.StatusOK, ListNotificationsResponse{Notifications: events})
+}
+
+// StreamNotifications 以 SSE 推送任务提醒，GET /notifications/stream?assignee=。
+// 客户端重连时通过 Last-Event-ID 继续接收，没有时只推送连接之后的提醒
+func StreamNotifications(ctx context.Context, c *app.RequestContext) {
+	feed, ok := notificationFeed(c)
+	if !ok {
+		return
+	}
+	assignee := c.Query("assignee")
+
+	var after int64
+	if id := sse.GetLastEventID(c); id != "" {
+		after, _ = strconv.ParseInt(id, 10, 64)
+	} else {
+		events, _ := feed.Since(0, assignee)
+		if len(events) > 0 {
+			after = events[len(events)-1].ID
+		}
+	}
+
+	s := sse.NewStream(c)
+	defer c.Flush()
+	for {
+		events, notify := feed.Since(after, assignee)
+		for _, e := range events {
+			data, err := json.Marshal(e)
+			if err != nil {
+				log.Printf("[Notification] Error marshaling reminder: %v\n", err)
+				return
+			}
+			err = s.Publish(&sse.Event{
+				ID:    strconv.FormatInt(e.ID, 10),
+				Event: EventReminder,
+				Data:  data,
+			})
+			if err != nil {
+				log.Printf("[Notification] Error publishing reminder: %v\n", err)
+				return
+			}
+			after = e.ID
+		}
+
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"log"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+
+	"meetingagent/pkg/prompts"
+)
+
+// summaryPrompt 返回会议类型对应的总结模板，没有该类型的模板时退回默认模板
+func summaryPrompt(meetingType string) (*prompts.Template, error) {
+	store, err := prompts.Default()
+	if err != nil {
+		return nil, err
+	}
+	return store.Get(prompts.Summary, meetingType)
+}
+
+// ReloadPrompts 重新读取 PROMPT_DIR 中的模板，之后的总结与对话使用新版本，进行中的对话不受影响
+func ReloadPrompts() error {
+	store, err := prompts.Default()
+	if err != nil {
+		return err
+	}
+	return store.Reload()
+}
+
+// ListPrompts 列出已加载的模板及其版本，GET /prompts
+func ListPrompts(ctx context.Context, c *app.RequestContext) {
+	store, err := prompts.Default()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+	list := store.List()
+	result := make([]utils.H, 0, len(list))
+	for _, t := range list {
+		result = append(result, utils.H{
+			"name":        t.Name,
+			"variant":     t.Variant,
+			"version":     t.Version,
+			"hash":        t.Hash,
+			"ref":         t.Ref(),
+			"description": t.Description,
+			"source":      t.Source,
+		})
+	}
+	c.JSON(consts.StatusOK, utils.H{"prompts": result})
+}
+
+// HandleReloadPrompts 重新加载模板，POST /prompts/reload。模板有误时返回 400 并继续使用之前的模板
+func HandleReloadPrompts(ctx context.Context, c *app.RequestContext) {
+	if err := ReloadPrompts(); err != nil {
+		log.Printf("[prompts] reload failed: %v", err)
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+		return
+	}
+	ListPrompts(ctx, c)
+}
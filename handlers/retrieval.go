@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"meetingagent/einoagent"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// retrievalOptions 解析对话请求中覆盖检索参数的 query 参数：
+//   - top_k: 返回的最大文档数
+//   - min_score: 最低相似度
+//   - meeting_only: 为 true 时只检索 meeting_id 对应会议的内容
+//   - speaker: 只检索包含该发言人的片段
+//   - date_from / date_to: 会议日期范围，格式 2006-01-02，包含两端
+func retrievalOptions(c *app.RequestContext) ([]compose.Option, error) {
+	var opts []retriever.Option
+
+	if v := c.Query("top_k"); v != "" {
+		topK, err := strconv.Atoi(v)
+		if err != nil || topK <= 0 {
+			return nil, fmt.Errorf("invalid top_k: %s", v)
+		}
+		opts = append(opts, retriever.WithTopK(topK))
+	}
+
+	if v := c.Query("min_score"); v != "" {
+		minScore, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min_score: %s", v)
+		}
+		opts = append(opts, retriever.WithScoreThreshold(minScore))
+	}
+
+	filter := &einoagent.RetrievalFilter{
+		Speaker: c.Query("speaker"),
+	}
+	if meetingOnly, _ := strconv.ParseBool(c.Query("meeting_only")); meetingOnly {
+		filter.MeetingID = c.Query("meeting_id")
+	}
+	if v := c.Query("date_from"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date_from: %s", v)
+		}
+		filter.DateFrom = t
+	}
+	if v := c.Query("date_to"); v != "" {
+		t, err := time.ParseInLocation("2006-01-02", v, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date_to: %s", v)
+		}
+		// 包含当天
+		filter.DateTo = t.Add(24*time.Hour - time.Second)
+	}
+	if *filter != (einoagent.RetrievalFilter{}) {
+		opts = append(opts, einoagent.WithRetrievalFilter(filter))
+	}
+
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	return []compose.Option{compose.WithRetrieverOption(opts...)}, nil
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+
+	"meetingagent/cmd/einoagent/agent"
+	"meetingagent/pkg/mem"
+)
+
+type ListSessionsResponse struct {
+	Sessions []mem.SessionInfo `json:"sessions"`
+}
+
+type GetSessionResponse struct {
+	mem.SessionInfo
+	Running    bool              `json:"running"`
+	Transcript []*schema.Message `json:"transcript"`
+}
+
+// UpdateSessionRequest 只修改出现的字段；metadata 中值为 null 的 key 会被删除
+type UpdateSessionRequest struct {
+	Title     *string            `json:"title"`
+	MeetingID *string            `json:"meeting_id"`
+	Metadata  map[string]*string `json:"metadata"`
+}
+
+// ListSessions 列出会话，按最后活动时间倒序，GET /sessions?meeting_id=
+func ListSessions(ctx context.Context, c *app.RequestContext) {
+	memory, ok := sessionMemory(c)
+	if !ok {
+		return
+	}
+	meetingID := c.Query("meeting_id")
+
+	sessions := make([]mem.SessionInfo, 0)
+	for _, id := range memory.ListConversations() {
+		conversation := memory.GetConversation(id, false)
+		if conversation == nil {
+			continue
+		}
+		info := conversation.Info()
+		if meetingID != "" && info.MeetingID != meetingID {
+			continue
+		}
+		sessions = append(sessions, info)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].UpdatedAt.After(sessions[j].UpdatedAt)
+	})
+
+	c.JSON(consts.StatusOK, ListSessionsResponse{Sessions: sessions})
+}
+
+// GetSession 返回会话信息与完整对话记录，GET /sessions/:id
+func GetSession(ctx context.Context, c *app.RequestContext) {
+	memory, ok := sessionMemory(c)
+	if !ok {
+		return
+	}
+	conversation, ok := getSession(c, memory)
+	if !ok {
+		return
+	}
+
+	c.JSON(consts.StatusOK, GetSessionResponse{
+		SessionInfo: conversation.Info(),
+		Running:     activeChatRun(c.Param("id")) != nil,
+		Transcript:  conversation.GetFullMessages(),
+	})
+}
+
+// UpdateSession 修改会话的标题、所属会议与元数据，PATCH /sessions/:id
+func UpdateSession(ctx context.Context, c *app.RequestContext) {
+	memory, ok := sessionMemory(c)
+	if !ok {
+		return
+	}
+	conversation, ok := getSession(c, memory)
+	if !ok {
+		return
+	}
+
+	var req UpdateSessionRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid request body"})
+		return
+	}
+
+	err := conversation.UpdateInfo(func(info *mem.SessionInfo) {
+		if req.Title != nil {
+			info.Title = *req.Title
+		}
+		if req.MeetingID != nil {
+			info.MeetingID = *req.MeetingID
+		}
+		for k, v := range req.Metadata {
+			if v == nil {
+				delete(info.Metadata, k)
+				continue
+			}
+			if info.Metadata == nil {
+				info.Metadata = make(map[string]string)
+			}
+			info.Metadata[k] = *v
+		}
+	})
+	if err != nil {
+		log.Printf("[Session] Error updating session %s: %v\n", c.Param("id"), err)
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, conversation.Info())
+}
+
+// stopTimeout 删除会话时等待进行中的对话停止的时间
+const stopTimeout = 30 * time.Second
+
+// DeleteSession 删除会话，进行中的对话会先被停止，DELETE /sessions/:id
+func DeleteSession(ctx context.Context, c *app.RequestContext) {
+	memory, ok := sessionMemory(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if !memory.HasConversation(id) {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "session not found"})
+		return
+	}
+
+	// 等待停止的对话把这一轮写入会话后再删除，否则之后的写入会重新创建会话
+	if run := activeChatRun(id); run != nil {
+		run.stop()
+		select {
+		case <-run.exited:
+		case <-time.After(stopTimeout):
+			c.JSON(consts.StatusConflict, utils.H{"error": "the running chat did not stop in time, please retry"})
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+	if err := memory.DeleteConversation(id); err != nil {
+		log.Printf("[Session] Error deleting session %s: %v\n", id, err)
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"id": id, "deleted": true})
+}
+
+func sessionMemory(c *app.RequestContext) (mem.Memory, bool) {
+	memory, err := agent.Memory()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return nil, false
+	}
+	return memory, true
+}
+
+func getSession(c *app.RequestContext, memory mem.Memory) (mem.Conversation, bool) {
+	id := c.Param("id")
+	if !memory.HasConversation(id) {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "session not found"})
+		return nil, false
+	}
+	conversation := memory.GetConversation(id, false)
+	if conversation == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "session not found"})
+		return nil, false
+	}
+	return conversation, true
+}
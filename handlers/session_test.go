@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/cloudwego/hertz/pkg/common/config"
+	"github.com/cloudwego/hertz/pkg/common/ut"
+	"github.com/cloudwego/hertz/pkg/route"
+
+	"meetingagent/cmd/einoagent/agent"
+)
+
+func TestDeleteSessionDuringRun(t *testing.T) {
+	t.Setenv("MEMORY_STORE", "file")
+	t.Setenv("MEMORY_DIR", t.TempDir())
+	t.Setenv("MEMORY_SUMMARY_ENABLED", "false")
+	memory, err := agent.Memory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const id = "delete-during-run"
+	conversation := memory.GetConversation(id, true)
+	conversation.Append(schema.UserMessage("上周的会议讲了什么"))
+	conversation.Append(schema.AssistantMessage("上周讨论了发布计划", nil))
+
+	run, err := newChatRun(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 模拟 agent：被停止后稍晚才把部分回答写入会话，随后结束对话
+	go func() {
+		<-run.ctx.Done()
+		time.Sleep(50 * time.Millisecond)
+		conversation.Append(schema.UserMessage("总结一下"))
+		conversation.Append(schema.AssistantMessage("部分回答", nil))
+		run.finish()
+	}()
+
+	engine := route.NewEngine(config.NewOptions(nil))
+	engine.DELETE("/sessions/:id", DeleteSession)
+	w := ut.PerformRequest(engine, http.MethodDelete, "/sessions/"+id, nil)
+	if code := w.Result().StatusCode(); code != http.StatusOK {
+		t.Fatalf("DELETE /sessions/%s = %d: %s", id, code, w.Result().Body())
+	}
+
+	if !run.stopped.Load() {
+		t.Error("the running chat was not stopped")
+	}
+	if activeChatRun(id) != nil {
+		t.Error("the chat is still running after the session was deleted")
+	}
+	select {
+	case <-run.exited:
+	case <-time.After(time.Second):
+		t.Fatal("the chat did not finish")
+	}
+	if memory.HasConversation(id) {
+		t.Error("the deleted session was recreated by the running chat")
+	}
+}
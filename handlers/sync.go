@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+
+	"meetingagent/pkg/tasksync"
+)
+
+type TaskSyncResponse struct {
+	Links []*tasksync.Link `json:"links"`
+}
+
+// GetTaskSync 任务在各外部任务系统中的关联与最近一次同步的结果，GET /tasks/:id/sync
+func GetTaskSync(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	id := c.Param("id")
+	if _, err := service.Get(id); err != nil {
+		taskError(c, err)
+		return
+	}
+	syncer, err := tasksync.Default()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+	links, err := syncer.Links(id)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	if links == nil {
+		links = make([]*tasksync.Link, 0)
+	}
+	c.JSON(consts.StatusOK, TaskSyncResponse{Links: links})
+}
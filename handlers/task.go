@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+
+	"meetingagent/pkg/tool/task"
+)
+
+// TaskResource 接口返回的任务，附带用于 If-Match 的 etag
+type TaskResource struct {
+	*task.Task
+	ETag string `json:"etag"`
+}
+
+type ListTasksResponse struct {
+	Tasks []*TaskResource `json:"tasks"`
+}
+
+type TaskHistoryResponse struct {
+	History []*task.Change `json:"history"`
+}
+
+func newTaskResource(t *task.Task) *TaskResource {
+	return &TaskResource{Task: t, ETag: task.ETag(t)}
+}
+
+func taskService(c *app.RequestContext) (*task.Service, bool) {
+	service, err := task.DefaultService()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return nil, false
+	}
+	return service, true
+}
+
+// taskError 按错误类型返回状态码
+func taskError(c *app.RequestContext, err error) {
+	status := consts.StatusInternalServerError
+	switch {
+	case errors.Is(err, task.ErrNotFound):
+		status = consts.StatusNotFound
+	case errors.Is(err, task.ErrInvalid):
+		status = consts.StatusBadRequest
+	case errors.Is(err, task.ErrPreconditionFailed):
+		status = consts.StatusPreconditionFailed
+	case errors.Is(err, task.ErrNotDeleted):
+		status = consts.StatusConflict
+	default:
+		log.Printf("[Task] Error handling %s %s: %v\n", c.Method(), c.Path(), err)
+	}
+	c.JSON(status, utils.H{"error": err.Error()})
+}
+
+// userContext 变更历史中记录为用户修改，用户名来自 X-User 请求头
+func userContext(ctx context.Context, c *app.RequestContext) context.Context {
+	return task.WithActor(ctx, task.Actor{Kind: task.ActorUser, ID: string(c.GetHeader("X-User"))})
+}
+
+// splitQuery 逗号分隔或重复出现的查询参数
+func splitQuery(c *app.RequestContext, key string) []string {
+	var values []string
+	c.QueryArgs().VisitAll(func(k, v []byte) {
+		if string(k) != key {
+			return
+		}
+		for _, value := range strings.Split(string(v), ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	})
+	return values
+}
+
+// ListTasks 按条件列出任务，deleted=true 时列出已删除的任务，
+// GET /tasks?q=&done=&assignee=&priority=&status=&tags=&source_meeting_id=&parent_id=&limit=&deleted=
+func ListTasks(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+
+	params := &task.ListParams{
+		Query:           c.Query("q"),
+		Assignee:        c.Query("assignee"),
+		Priority:        task.Priority(c.Query("priority")),
+		Tags:            splitQuery(c, "tags"),
+		SourceMeetingID: c.Query("source_meeting_id"),
+		ParentID:        c.Query("parent_id"),
+	}
+	if v := c.Query("deleted"); v != "" {
+		deleted, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid deleted: " + v})
+			return
+		}
+		params.Deleted = deleted
+	}
+	if v := c.Query("done"); v != "" {
+		done, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid done: " + v})
+			return
+		}
+		params.IsDone = &done
+	}
+	if params.Priority != "" && !params.Priority.Valid() {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid priority: " + string(params.Priority)})
+		return
+	}
+	for _, v := range splitQuery(c, "status") {
+		status := task.Status(v)
+		if !status.Valid() {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid status: " + v})
+			return
+		}
+		params.Status = append(params.Status, status)
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 0 {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid limit: " + v})
+			return
+		}
+		params.Limit = &limit
+	}
+
+	tasks, err := service.List(params)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	resources := make([]*TaskResource, 0, len(tasks))
+	for _, t := range tasks {
+		resources = append(resources, newTaskResource(t))
+	}
+	c.JSON(consts.StatusOK, ListTasksResponse{Tasks: resources})
+}
+
+// CreateTask 添加任务，返回 201 与新任务的地址，POST /tasks
+func CreateTask(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	var req task.Task
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid request body"})
+		return
+	}
+
+	created, err := service.Create(userContext(ctx, c), &req)
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	resource := newTaskResource(created)
+	c.Header("Location", "/tasks/"+created.ID)
+	c.Header("ETag", resource.ETag)
+	c.JSON(consts.StatusCreated, resource)
+}
+
+// GetTask 返回任务，If-None-Match 与当前 ETag 相同时返回 304，GET /tasks/:id
+func GetTask(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	t, err := service.Get(c.Param("id"))
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	resource := newTaskResource(t)
+	c.Header("ETag", resource.ETag)
+	if match := string(c.GetHeader("If-None-Match")); match != "" && strings.Contains(match, resource.ETag) {
+		c.Status(consts.StatusNotModified)
+		return
+	}
+	c.JSON(consts.StatusOK, resource)
+}
+
+// UpdateTask 只修改出现的字段，带 If-Match 时任务在此期间被修改过会返回 412，PATCH /tasks/:id
+func UpdateTask(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	var req task.TaskPatch
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid request body"})
+		return
+	}
+
+	updated, err := service.Patch(userContext(ctx, c), c.Param("id"), &req, string(c.GetHeader("If-Match")))
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	resource := newTaskResource(updated)
+	c.Header("ETag", resource.ETag)
+	c.JSON(consts.StatusOK, resource)
+}
+
+// DeleteTask 删除任务，返回 204，If-Match 的处理与 UpdateTask 相同。
+// 默认只标记删除，可以通过 restore 恢复；purge=true 时彻底删除，变更历史保留，
+// DELETE /tasks/:id?purge=
+func DeleteTask(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	purge, err := strconv.ParseBool(c.DefaultQuery("purge", "false"))
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "invalid purge: " + c.Query("purge")})
+		return
+	}
+
+	ctx, id, ifMatch := userContext(ctx, c), c.Param("id"), string(c.GetHeader("If-Match"))
+	if purge {
+		err = service.Purge(ctx, id, ifMatch)
+	} else {
+		err = service.Delete(ctx, id, ifMatch)
+	}
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.Status(consts.StatusNoContent)
+}
+
+// RestoreTask 恢复已删除的任务，任务没有被删除时返回 409，POST /tasks/:id/restore
+func RestoreTask(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	restored, err := service.Restore(userContext(ctx, c), c.Param("id"), string(c.GetHeader("If-Match")))
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	resource := newTaskResource(restored)
+	c.Header("ETag", resource.ETag)
+	c.JSON(consts.StatusOK, resource)
+}
+
+// GetTaskHistory 任务的变更历史，按时间顺序，彻底删除的任务也可以查询，GET /tasks/:id/history
+func GetTaskHistory(ctx context.Context, c *app.RequestContext) {
+	service, ok := taskService(c)
+	if !ok {
+		return
+	}
+	changes, err := service.History(c.Param("id"))
+	if err != nil {
+		taskError(c, err)
+		return
+	}
+	c.JSON(consts.StatusOK, TaskHistoryResponse{History: changes})
+}
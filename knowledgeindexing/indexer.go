@@ -18,67 +18,31 @@ package knowledgeindexing
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"log"
-	"os"
 
-	"github.com/cloudwego/eino-ext/components/indexer/redis"
 	"github.com/cloudwego/eino/components/indexer"
-	"github.com/cloudwego/eino/schema"
-	"github.com/google/uuid"
-	redisCli "github.com/redis/go-redis/v9"
 
-	redispkg "github.com/cloudwego/eino-examples/quickstart/eino_assistant/pkg/redis"
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/vectorstore"
 )
 
-func init() {
-	err := redispkg.Init()
-	if err != nil {
-		log.Fatalf("failed to init redis index: %v", err)
-	}
-}
-
-// newIndexer component initialization function of node 'RedisIndexer' in graph 'KnowledgeIndexing'
+// newIndexer component initialization function of node 'VectorIndexer' in graph 'KnowledgeIndexing'
 func newIndexer(ctx context.Context) (idr indexer.Indexer, err error) {
 	// TODO Modify component configuration here.
-	redisAddr := os.Getenv("REDIS_ADDR")
-	redisClient := redisCli.NewClient(&redisCli.Options{
-		Addr:     redisAddr,
-		Protocol: 2,
-	})
-
-	config := &redis.IndexerConfig{
-		Client:    redisClient,
-		KeyPrefix: redispkg.RedisPrefix,
-		BatchSize: 1,
-		DocumentToHashes: func(ctx context.Context, doc *schema.Document) (*redis.Hashes, error) {
-			if doc.ID == "" {
-				doc.ID = uuid.New().String()
-			}
-			key := doc.ID
-
-			metadataBytes, err := json.Marshal(doc.MetaData)
-			if err != nil {
-				return nil, fmt.Errorf("failed to marshal metadata: %w", err)
-			}
-
-			return &redis.Hashes{
-				Key: key,
-				Field2Value: map[string]redis.FieldValue{
-					redispkg.ContentField:  {Value: doc.Content, EmbedKey: redispkg.VectorField},
-					redispkg.MetadataField: {Value: metadataBytes},
-				},
-			}, nil
-		},
+	store, err := vectorstore.Default(ctx)
+	if err != nil {
+		return nil, err
+	}
+	config := &vectorstore.IndexerConfig{
+		Store: store,
+		// 每次调用 Embedder 时提交的文档数，INDEX_BATCH_SIZE，默认 10
+		BatchSize: env.GetInt("INDEX_BATCH_SIZE", 10),
 	}
-
 	embeddingIns11, err := newEmbedding(ctx)
 	if err != nil {
 		return nil, err
 	}
 	config.Embedding = embeddingIns11
-	idr, err = redis.NewIndexer(ctx, config)
+	idr, err = vectorstore.NewIndexer(ctx, config)
 	if err != nil {
 		return nil, err
 	}
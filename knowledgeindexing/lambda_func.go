@@ -0,0 +1,49 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package knowledgeindexing
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/document/loader/file"
+	"github.com/cloudwego/eino/schema"
+
+	"meetingagent/pkg/vectorstore"
+)
+
+// newLambda component initialization function of node 'MetadataEnricher' in graph 'KnowledgeIndexing'
+// 从文件路径和内容中提取会议 ID、发言人和会议日期，写入文档元数据以便检索时过滤
+func newLambda(ctx context.Context, docs []*schema.Document) (output []*schema.Document, err error) {
+	for _, doc := range docs {
+		if doc.MetaData == nil {
+			doc.MetaData = make(map[string]any)
+		}
+		source, _ := doc.MetaData[file.MetaKeySource].(string)
+		if source == "" {
+			continue
+		}
+
+		doc.MetaData[vectorstore.MetaMeetingID] = MeetingIDFromPath(source)
+		if speakers := ParseSpeakers(doc.Content); len(speakers) > 0 {
+			doc.MetaData[vectorstore.MetaSpeaker] = speakers
+		}
+		if date, ok := MeetingDateFromPath(source); ok {
+			doc.MetaData[vectorstore.MetaDate] = date.Unix()
+		}
+	}
+	return docs, nil
+}
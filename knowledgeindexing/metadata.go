@@ -0,0 +1,82 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package knowledgeindexing
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// meetingFileTimeLayout 会议文件名中的时间戳格式，如 meetings/20250422_135036.md
+const meetingFileTimeLayout = "20060102_150405"
+
+var (
+	partSuffix  = regexp.MustCompile(`\.part\d+$`)
+	speakerLine = regexp.MustCompile(`(?m)^[\d:.]+-[\d:.]+ ([^:：\n]+)[:：]`)
+)
+
+// meetingBaseName 去掉目录、扩展名以及切分产生的 .partN 后缀
+func meetingBaseName(path string) string {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return partSuffix.ReplaceAllString(name, "")
+}
+
+// IsPartFile 是否为切分长会议记录时生成的 .partN.md 文件
+func IsPartFile(path string) bool {
+	return partSuffix.MatchString(strings.TrimSuffix(path, filepath.Ext(path)))
+}
+
+// MeetingIDFromPath 根据会议文件路径推导会议 ID，与 POST /meeting 返回的 ID 保持一致，
+// 例如 meetings/20250422_135036.part1.md -> meeting_20250422135036
+func MeetingIDFromPath(path string) string {
+	name := meetingBaseName(path)
+	if _, err := time.ParseInLocation(meetingFileTimeLayout, name, time.Local); err == nil {
+		return "meeting_" + strings.ReplaceAll(name, "_", "")
+	}
+	return name
+}
+
+// MeetingDateFromPath 根据会议文件名中的时间戳解析会议时间
+func MeetingDateFromPath(path string) (time.Time, bool) {
+	t, err := time.ParseInLocation(meetingFileTimeLayout, meetingBaseName(path), time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// MeetingFileName 根据会议时间生成会议文件名
+func MeetingFileName(t time.Time) string {
+	return t.Format(meetingFileTimeLayout) + ".md"
+}
+
+// ParseSpeakers 从 "00:00:00-00:00:45 Lily: ..." 格式的会议记录中提取发言人，按出现顺序去重
+func ParseSpeakers(content string) []string {
+	seen := make(map[string]struct{})
+	speakers := make([]string, 0)
+	for _, m := range speakerLine.FindAllStringSubmatch(content, -1) {
+		name := strings.TrimSpace(m[1])
+		if _, ok := seen[name]; ok || name == "" {
+			continue
+		}
+		seen[name] = struct{}{}
+		speakers = append(speakers, name)
+	}
+	return speakers
+}
@@ -27,7 +27,8 @@ func BuildKnowledgeIndexing(ctx context.Context) (r compose.Runnable[document.So
 	const (
 		FileLoader       = "FileLoader"
 		MarkdownSplitter = "MarkdownSplitter"
-		RedisIndexer     = "RedisIndexer"
+		MetadataEnricher = "MetadataEnricher"
+		VectorIndexer    = "VectorIndexer"
 	)
 	g := compose.NewGraph[document.Source, []string]()
 	fileLoaderKeyOfLoader, err := newLoader(ctx)
@@ -40,15 +41,17 @@ func BuildKnowledgeIndexing(ctx context.Context) (r compose.Runnable[document.So
 		return nil, err
 	}
 	_ = g.AddDocumentTransformerNode(MarkdownSplitter, markdownSplitterKeyOfDocumentTransformer)
-	redisIndexerKeyOfIndexer, err := newIndexer(ctx)
+	_ = g.AddLambdaNode(MetadataEnricher, compose.InvokableLambda(newLambda), compose.WithNodeName("MetadataEnricher"))
+	vectorIndexerKeyOfIndexer, err := newIndexer(ctx)
 	if err != nil {
 		return nil, err
 	}
-	_ = g.AddIndexerNode(RedisIndexer, redisIndexerKeyOfIndexer)
+	_ = g.AddIndexerNode(VectorIndexer, vectorIndexerKeyOfIndexer)
 	_ = g.AddEdge(compose.START, FileLoader)
-	_ = g.AddEdge(RedisIndexer, compose.END)
+	_ = g.AddEdge(VectorIndexer, compose.END)
 	_ = g.AddEdge(FileLoader, MarkdownSplitter)
-	_ = g.AddEdge(MarkdownSplitter, RedisIndexer)
+	_ = g.AddEdge(MarkdownSplitter, MetadataEnricher)
+	_ = g.AddEdge(MetadataEnricher, VectorIndexer)
 	r, err = g.Compile(ctx, compose.WithGraphName("KnowledgeIndexing"), compose.WithNodeTriggerMode(compose.AnyPredecessor))
 	if err != nil {
 		return nil, err
@@ -6,6 +6,10 @@ type Meeting struct {
 	Content   interface{} `json:"content"`
 	Summary   string      `json:"summary"`
 	CreatedAt string      `json:"created_at"`
+	// MeetingType selects the summary template, e.g. standup, review or seminar
+	MeetingType string `json:"meeting_type,omitempty"`
+	// PromptVersion is the template version the summary was generated with
+	PromptVersion string `json:"prompt_version,omitempty"`
 }
 
 // PostMeetingResponse represents the response for creating a meeting
@@ -0,0 +1,662 @@
+package deadline
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"meetingagent/pkg/env"
+)
+
+// ErrUnresolved 无法识别的表达
+var ErrUnresolved = errors.New("unrecognized deadline expression")
+
+// Resolution 解析结果
+type Resolution struct {
+	// Text 原始表达
+	Text string `json:"text"`
+	// Time 截止时间，表达中没有具体时刻时取当天 23:59:59
+	Time time.Time `json:"time"`
+	// DateOnly 表达中只有日期
+	DateOnly bool `json:"date_only"`
+}
+
+// RFC3339 截止时间的 RFC3339 格式，时区与锚点相同
+func (r *Resolution) RFC3339() string {
+	return r.Time.Format(time.RFC3339)
+}
+
+// Resolver 将"下周五"、"end of sprint" 等相对表达解析为具体时间。
+// 相对表达以锚点（通常是会议日期）为准，一周从周一开始
+type Resolver struct {
+	// SprintDays 迭代的天数
+	SprintDays int
+	// SprintStart 任一迭代的开始日期，用于推算迭代边界；为零值时视锚点为迭代的第一天
+	SprintStart time.Time
+}
+
+// NewResolverFromEnv 按环境变量创建：
+//   - DEADLINE_SPRINT_DAYS: 迭代天数，默认 14
+//   - DEADLINE_SPRINT_START: 任一迭代的开始日期 YYYY-MM-DD，默认以锚点为迭代开始
+func NewResolverFromEnv() *Resolver {
+	r := &Resolver{SprintDays: env.GetInt("DEADLINE_SPRINT_DAYS", 14)}
+	if v := env.GetString("DEADLINE_SPRINT_START", ""); v != "" {
+		if start, err := time.Parse(time.DateOnly, v); err == nil {
+			r.SprintStart = start
+		}
+	}
+	return r
+}
+
+// Resolve 使用环境变量中的配置解析 text
+func Resolve(text string, anchor time.Time) (*Resolution, error) {
+	return NewResolverFromEnv().Resolve(text, anchor)
+}
+
+// absoluteLayouts 已经是具体时间的写法，原样使用
+var absoluteLayouts = []struct {
+	layout   string
+	dateOnly bool
+}{
+	{time.RFC3339, false},
+	{"2006-01-02T15:04", false},
+	{"2006-01-02 15:04:05", false},
+	{"2006-01-02 15:04", false},
+	{time.DateOnly, true},
+	{"2006/01/02", true},
+	{"2006/1/2", true},
+	{"2006-1-2", true},
+}
+
+// Resolve 解析 text，锚点的时区即结果的时区
+func (r *Resolver) Resolve(text string, anchor time.Time) (*Resolution, error) {
+	res := &Resolution{Text: text}
+	s := normalize(text)
+	if s == "" {
+		return nil, fmt.Errorf("%w: %q", ErrUnresolved, text)
+	}
+
+	loc := anchor.Location()
+	for _, l := range absoluteLayouts {
+		if t, err := time.ParseInLocation(l.layout, strings.ToUpper(s), loc); err == nil {
+			res.Time, res.DateOnly = t, l.dateOnly
+			if l.dateOnly {
+				res.Time = endOfDay(t)
+			}
+			return res, nil
+		}
+	}
+
+	// "3天前""两周以前"是过去的时间，去掉"前"后会被当作三天后，不作为截止时间
+	if agoPattern.MatchString(strings.ReplaceAll(s, " ", "")) {
+		return nil, fmt.Errorf("%w: %q is in the past", ErrUnresolved, text)
+	}
+
+	s = stripAffixes(s)
+	s, hour, minute, hasTime := extractTimeOfDay(s)
+	s = stripAffixes(s)
+
+	day := midnight(anchor)
+	if s != "" {
+		var ok bool
+		if day, ok = r.resolveDate(s, midnight(anchor)); !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnresolved, text)
+		}
+	} else if !hasTime {
+		return nil, fmt.Errorf("%w: %q", ErrUnresolved, text)
+	}
+
+	if hasTime {
+		res.Time = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	} else {
+		res.Time, res.DateOnly = endOfDay(day), true
+	}
+	return res, nil
+}
+
+// resolveDate 依次尝试各条规则，today 为锚点当天零点。中文规则匹配去掉空格后的文本
+func (r *Resolver) resolveDate(s string, today time.Time) (time.Time, bool) {
+	compact := strings.ReplaceAll(s, " ", "")
+	for _, rule := range dateRules {
+		m := rule.re.FindStringSubmatch(s)
+		if m == nil {
+			m = rule.re.FindStringSubmatch(compact)
+		}
+		if m == nil {
+			continue
+		}
+		if day, ok := rule.resolve(r, m, today); ok {
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+type dateRule struct {
+	re      *regexp.Regexp
+	resolve func(r *Resolver, m []string, today time.Time) (time.Time, bool)
+}
+
+func fixed(days int) func(*Resolver, []string, time.Time) (time.Time, bool) {
+	return func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+		return today.AddDate(0, 0, days), true
+	}
+}
+
+const (
+	cnNumber = `\d+|[零一二两三四五六七八九十]+`
+	enNumber = `\d+|a|an|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve`
+	enMonth  = `january|jan|february|feb|march|mar|april|apr|may|june|jun|july|jul|august|aug|september|sept|sep|october|oct|november|nov|december|dec`
+	enDay    = `monday|mon|tuesday|tues|tue|wednesday|wed|thursday|thurs|thur|thu|friday|fri|saturday|sat|sunday|sun`
+)
+
+var dateRules = []dateRule{
+	{regexp.MustCompile(`^(今天|今日|今晚|当天|today|tonight|eod|end of (the )?day)$`), fixed(0)},
+	{regexp.MustCompile(`^(明天|明日|明晚|tomorrow)$`), fixed(1)},
+	{regexp.MustCompile(`^(后天|(the )?day after tomorrow)$`), fixed(2)},
+	{regexp.MustCompile(`^大后天$`), fixed(3)},
+
+	// 三天后、两周内、5个工作日内、一个月后
+	{regexp.MustCompile(`^(` + cnNumber + `)(个)?(天|日|工作日|周|星期|礼拜|月)(后|之后|以后|内|之内|以内)?$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			n, ok := parseNumber(m[1])
+			if !ok {
+				return time.Time{}, false
+			}
+			// "3月" 是月份、"10日" 是日期，时长写作 "3个月"、"10日内"
+			if (m[3] == "月" && m[2] == "") || (m[3] == "日" && m[4] == "") {
+				return time.Time{}, false
+			}
+			return addUnit(today, n, m[3]), true
+		}},
+	// in 3 days、within two weeks、in 5 business days、3 days from now
+	{regexp.MustCompile(`^(?:in|within) (` + enNumber + `) (business day|working day|day|week|month)s?$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			n, ok := parseNumber(m[1])
+			return addUnit(today, n, m[2]), ok
+		}},
+	{regexp.MustCompile(`^(` + enNumber + `) (business day|working day|day|week|month)s? (?:from now|later)$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			n, ok := parseNumber(m[1])
+			return addUnit(today, n, m[2]), ok
+		}},
+
+	// 周五、本周五、下周一、下下周三，不带前缀时为锚点当天或之后最近的一天
+	{regexp.MustCompile(`^(本|这|这个|下|下个|下下|下下个)?(?:周|星期|礼拜)([一二三四五六日天1-7])$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			weekday := cnWeekday(m[2])
+			switch m[1] {
+			case "":
+				return upcoming(today, weekday), true
+			case "下", "下个":
+				return inWeek(today, 1, weekday), true
+			case "下下", "下下个":
+				return inWeek(today, 2, weekday), true
+			default:
+				return inWeek(today, 0, weekday), true
+			}
+		}},
+	// friday、this friday、next friday，next 指下一周中的那一天，与"下周五"相同
+	{regexp.MustCompile(`^(this |next |coming )?(` + enDay + `)$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			weekday, ok := enWeekday(m[2])
+			if !ok {
+				return time.Time{}, false
+			}
+			switch strings.TrimSpace(m[1]) {
+			case "next":
+				return inWeek(today, 1, weekday), true
+			case "this":
+				return inWeek(today, 0, weekday), true
+			default:
+				return upcoming(today, weekday), true
+			}
+		}},
+
+	// 本周、这周内、end of week 指本周五；周末指周日
+	{regexp.MustCompile(`^(本周|这周|这个星期|本星期|这个礼拜|本周内|这周内|this week|(the )?end of (the |this )?week|eow)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return inWeek(today, 0, time.Friday), true
+		}},
+	{regexp.MustCompile(`^(周末|本周末|这周末|this weekend|weekend)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return inWeek(today, 0, time.Sunday), true
+		}},
+	{regexp.MustCompile(`^(下周|下个星期|下星期|下个礼拜|下周内|next week|(the )?end of next week)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return inWeek(today, 1, time.Friday), true
+		}},
+	{regexp.MustCompile(`^(下周末|next weekend)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return inWeek(today, 1, time.Sunday), true
+		}},
+	{regexp.MustCompile(`^(下周初|early next week|(the )?beginning of next week)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return inWeek(today, 1, time.Monday), true
+		}},
+
+	// 月底、下个月底、下月初、月中
+	{regexp.MustCompile(`^((本|这个)?月(底|末)|本月内?|这个月|this month|(the )?end of (the |this )?month|eom)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return endOfMonth(today, 0), true
+		}},
+	{regexp.MustCompile(`^((下个?月)(底|末)?|next month|(the )?end of next month)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return endOfMonth(today, 1), true
+		}},
+	{regexp.MustCompile(`^(下个?月初|early next month|(the )?beginning of next month|(the )?start of next month)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+		}},
+	{regexp.MustCompile(`^((本|这个)?月中|mid-?month)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return time.Date(today.Year(), today.Month(), 15, 0, 0, 0, 0, today.Location()), true
+		}},
+	// 6月底、六月初、6月中，已经过去的月份指明年
+	{regexp.MustCompile(`^(` + cnNumber + `)月(底|末|初|中|中旬|上旬|下旬)$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			month, ok := parseNumber(m[1])
+			if !ok || month < 1 || month > 12 {
+				return time.Time{}, false
+			}
+			day := 1
+			switch m[2] {
+			case "底", "末", "下旬":
+				day = daysIn(today.Year(), time.Month(month))
+			case "中", "中旬":
+				day = 15
+			case "上旬":
+				day = 10
+			}
+			return nextDate(today, 0, time.Month(month), day), true
+		}},
+
+	// 季度末、下季度
+	{regexp.MustCompile(`^((本|这个)?季度(末|底|内)?|季末|this quarter|(the )?end of (the |this )?quarter|eoq)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return endOfQuarter(today, 0), true
+		}},
+	{regexp.MustCompile(`^(下个?季度(末|底)?|next quarter|(the )?end of next quarter)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return endOfQuarter(today, 1), true
+		}},
+	// 年底
+	{regexp.MustCompile(`^((今|本)?年(底|末)|今年年底|今年内?|this year|(the )?end of (the |this )?year|eoy)$`),
+		func(_ *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()), true
+		}},
+
+	// 迭代结束、end of sprint、下个迭代
+	{regexp.MustCompile(`^(本|这个|当前)?(迭代|冲刺|sprint)(结束|末|内)?$|^(the )?end of (the |this |current )?sprint$|^(this )?sprint end$|^eos$`),
+		func(r *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return r.endOfSprint(today, 0)
+		}},
+	{regexp.MustCompile(`^下个?(迭代|冲刺|sprint)(结束|末|内)?$|^(the )?end of (the )?next sprint$|^next sprint$`),
+		func(r *Resolver, _ []string, today time.Time) (time.Time, bool) {
+			return r.endOfSprint(today, 1)
+		}},
+
+	// 2025年5月10日、5月10号、五月十日，未写年份且已经过去时指明年
+	{regexp.MustCompile(`^(?:(\d{4})年)?(` + cnNumber + `)月(` + cnNumber + `)[日号]?$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			return dateOf(today, m[1], m[2], m[3])
+		}},
+	// 5/10、5.10，月在前
+	{regexp.MustCompile(`^(\d{1,2})[/.](\d{1,2})$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			return dateOf(today, "", m[1], m[2])
+		}},
+	// 10号：本月，已经过去时指下个月
+	{regexp.MustCompile(`^(?:本月)?(` + cnNumber + `)[日号]$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			day, ok := parseNumber(m[1])
+			if !ok || day < 1 || day > 31 {
+				return time.Time{}, false
+			}
+			t := time.Date(today.Year(), today.Month(), day, 0, 0, 0, 0, today.Location())
+			if t.Before(today) {
+				t = time.Date(today.Year(), today.Month()+1, day, 0, 0, 0, 0, today.Location())
+			}
+			return t, t.Day() == day
+		}},
+	// may 10、may 10th, 2025、10 may
+	{regexp.MustCompile(`^(` + enMonth + `)\.? (\d{1,2})(?:st|nd|rd|th)?(?:,? (\d{4}))?$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			month, ok := enMonthOf(m[1])
+			if !ok {
+				return time.Time{}, false
+			}
+			return dateOf(today, m[3], strconv.Itoa(int(month)), m[2])
+		}},
+	{regexp.MustCompile(`^(\d{1,2})(?:st|nd|rd|th)? (?:of )?(` + enMonth + `)\.?(?:,? (\d{4}))?$`),
+		func(_ *Resolver, m []string, today time.Time) (time.Time, bool) {
+			month, ok := enMonthOf(m[2])
+			if !ok {
+				return time.Time{}, false
+			}
+			return dateOf(today, m[3], strconv.Itoa(int(month)), m[1])
+		}},
+}
+
+// agoPattern 数量加时长再加"前"，例如 3天前、两个月以前。
+// "周五前""10号前""10日前""3月前"是具体日期之前，不在此列
+var agoPattern = regexp.MustCompile(`^(` + cnNumber + `)(个月|个?(天|工作日|周|星期|礼拜|年|小时|钟头|分钟))(之|以)?前$`)
+
+var (
+	prefixes = []string{"no later than ", "by the ", "before the ", "by ", "before ", "due ", "until ", "till ", "on ",
+		"截止到", "截止至", "截止", "截至", "最晚", "最迟", "要在", "需在", "在", "于", "到"}
+	suffixes = []string{"之前", "以前", "为止", "截止", "前", " at the latest", " latest"}
+)
+
+// stripAffixes 去掉"截止""之前""by"等不影响日期的词
+func stripAffixes(s string) string {
+	for changed := true; changed; {
+		changed = false
+		s = strings.TrimSpace(s)
+		for _, p := range prefixes {
+			if strings.HasPrefix(s, p) && len(s) > len(p) {
+				s, changed = s[len(p):], true
+			}
+		}
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(s, suffix) && len(s) > len(suffix) {
+				s, changed = s[:len(s)-len(suffix)], true
+			}
+		}
+		s = strings.TrimSuffix(strings.TrimSpace(s), "的")
+	}
+	return strings.TrimSpace(s)
+}
+
+// normalize 转为小写，全角数字与符号转为半角，合并空白，去掉结尾的标点
+func normalize(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= '０' && r <= '９':
+			r = r - '０' + '0'
+		case r == '：':
+			r = ':'
+		case r == '／':
+			r = '/'
+		case r == '，':
+			r = ','
+		}
+		b.WriteRune(r)
+	}
+	s := strings.Join(strings.Fields(b.String()), " ")
+	return strings.TrimRight(s, "。.!！;；")
+}
+
+var (
+	cnTimeRe = regexp.MustCompile(`(上午|早上|早晨|中午|下午|傍晚|晚上|今晚|明晚)?(\d{1,2}|[零一二两三四五六七八九十]+)[点點时](半|(\d{1,2}|[零一二三四五六七八九十]+)分?)?(钟)?`)
+	enTimeRe = regexp.MustCompile(`(?:\bat )?\b(\d{1,2})(?::(\d{2}))? ?(am|pm)\b`)
+	hmTimeRe = regexp.MustCompile(`(?:\bat )?\b(\d{1,2}):(\d{2})\b`)
+	noonRe   = regexp.MustCompile(`(?:\bat )?\bnoon\b|中午12点|中午`)
+)
+
+// extractTimeOfDay 取出并去掉文本中的时刻，例如"下午3点"、"3pm"、"15:00"
+func extractTimeOfDay(s string) (rest string, hour, minute int, ok bool) {
+	if m := cnTimeRe.FindStringSubmatchIndex(s); m != nil {
+		sub := func(i int) string {
+			if m[2*i] < 0 {
+				return ""
+			}
+			return s[m[2*i]:m[2*i+1]]
+		}
+		h, hok := parseNumber(sub(2))
+		if hok && h <= 24 {
+			switch sub(1) {
+			case "下午", "傍晚", "晚上", "今晚", "明晚":
+				if h < 12 {
+					h += 12
+				}
+			case "中午":
+				if h < 6 {
+					h += 12
+				}
+			}
+			min := 0
+			if sub(3) == "半" {
+				min = 30
+			} else if sub(4) != "" {
+				min, _ = parseNumber(sub(4))
+			}
+			// 时段中的"今晚""明晚"同时表示日期
+			prefix := ""
+			if p := sub(1); p == "今晚" || p == "明晚" {
+				prefix = p
+			}
+			if h <= 23 && min < 60 {
+				return strings.TrimSpace(s[:m[0]] + prefix + " " + s[m[1]:]), h, min, true
+			}
+		}
+	}
+	if m := enTimeRe.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		if h >= 1 && h <= 12 && min < 60 {
+			if m[3] == "pm" && h < 12 {
+				h += 12
+			}
+			if m[3] == "am" && h == 12 {
+				h = 0
+			}
+			return strings.TrimSpace(strings.Replace(s, m[0], " ", 1)), h, min, true
+		}
+	}
+	if m := hmTimeRe.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[1])
+		min, _ := strconv.Atoi(m[2])
+		if h <= 23 && min < 60 {
+			return strings.TrimSpace(strings.Replace(s, m[0], " ", 1)), h, min, true
+		}
+	}
+	if m := noonRe.FindString(s); m != "" {
+		return strings.TrimSpace(strings.Replace(s, m, " ", 1)), 12, 0, true
+	}
+	return s, 0, 0, false
+}
+
+var enNumbers = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+var cnDigits = map[rune]int{
+	'零': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9,
+}
+
+// parseNumber 解析阿拉伯数字、英文数字或 99 以内的中文数字
+func parseNumber(s string) (int, bool) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n, true
+	}
+	if n, ok := enNumbers[s]; ok {
+		return n, true
+	}
+
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, false
+	}
+	i := strings.IndexRune(s, '十')
+	if i < 0 {
+		if len(runes) != 1 {
+			return 0, false
+		}
+		n, ok := cnDigits[runes[0]]
+		return n, ok
+	}
+	// 十、十五、二十、二十五
+	tens, ones := 1, 0
+	before, after := []rune(s[:i]), []rune(s[i+len("十"):])
+	if len(before) > 1 || len(after) > 1 {
+		return 0, false
+	}
+	if len(before) == 1 {
+		n, ok := cnDigits[before[0]]
+		if !ok {
+			return 0, false
+		}
+		tens = n
+	}
+	if len(after) == 1 {
+		n, ok := cnDigits[after[0]]
+		if !ok {
+			return 0, false
+		}
+		ones = n
+	}
+	return tens*10 + ones, true
+}
+
+func cnWeekday(s string) time.Weekday {
+	switch s {
+	case "日", "天", "7":
+		return time.Sunday
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		return time.Weekday(n)
+	}
+	n, _ := parseNumber(s)
+	return time.Weekday(n)
+}
+
+func enWeekday(s string) (time.Weekday, bool) {
+	switch s[:3] {
+	case "mon":
+		return time.Monday, true
+	case "tue":
+		return time.Tuesday, true
+	case "wed":
+		return time.Wednesday, true
+	case "thu":
+		return time.Thursday, true
+	case "fri":
+		return time.Friday, true
+	case "sat":
+		return time.Saturday, true
+	case "sun":
+		return time.Sunday, true
+	}
+	return 0, false
+}
+
+func enMonthOf(s string) (time.Month, bool) {
+	for m := time.January; m <= time.December; m++ {
+		if strings.HasPrefix(strings.ToLower(m.String()), s[:3]) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+// addUnit 在 today 上增加 n 个单位，工作日跳过周六周日
+func addUnit(today time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "周", "星期", "礼拜", "week":
+		return today.AddDate(0, 0, 7*n)
+	case "月", "month":
+		return today.AddDate(0, n, 0)
+	case "工作日", "business day", "working day":
+		t := today
+		for n > 0 {
+			t = t.AddDate(0, 0, 1)
+			if t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+				n--
+			}
+		}
+		return t
+	default:
+		return today.AddDate(0, 0, n)
+	}
+}
+
+// mondayOffset 周一为 0，周日为 6
+func mondayOffset(w time.Weekday) int {
+	return (int(w) + 6) % 7
+}
+
+// inWeek 锚点所在周之后第 weeks 周中的 weekday
+func inWeek(today time.Time, weeks int, weekday time.Weekday) time.Time {
+	monday := today.AddDate(0, 0, -mondayOffset(today.Weekday()))
+	return monday.AddDate(0, 0, 7*weeks+mondayOffset(weekday))
+}
+
+// upcoming 锚点当天或之后最近的 weekday
+func upcoming(today time.Time, weekday time.Weekday) time.Time {
+	return today.AddDate(0, 0, (int(weekday)-int(today.Weekday())+7)%7)
+}
+
+func endOfMonth(today time.Time, months int) time.Time {
+	return time.Date(today.Year(), today.Month()+time.Month(months)+1, 0, 0, 0, 0, 0, today.Location())
+}
+
+func endOfQuarter(today time.Time, quarters int) time.Time {
+	first := time.Month((int(today.Month())-1)/3*3 + 1)
+	return time.Date(today.Year(), first+time.Month(3*(quarters+1)), 0, 0, 0, 0, 0, today.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nextDate 年份为 0 时取今年，已经过去则取明年
+func nextDate(today time.Time, year int, month time.Month, day int) time.Time {
+	explicit := year != 0
+	if !explicit {
+		year = today.Year()
+	}
+	t := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if !explicit && t.Before(today) {
+		t = time.Date(year+1, month, min(day, daysIn(year+1, month)), 0, 0, 0, 0, today.Location())
+	}
+	return t
+}
+
+func dateOf(today time.Time, year, month, day string) (time.Time, bool) {
+	y := 0
+	if year != "" {
+		y, _ = strconv.Atoi(year)
+	}
+	m, ok := parseNumber(month)
+	if !ok || m < 1 || m > 12 {
+		return time.Time{}, false
+	}
+	d, ok := parseNumber(day)
+	if !ok || d < 1 || d > daysIn(max(y, today.Year()), time.Month(m)) {
+		return time.Time{}, false
+	}
+	return nextDate(today, y, time.Month(m), d), true
+}
+
+// endOfSprint 锚点所在迭代之后第 sprints 个迭代的最后一天
+func (r *Resolver) endOfSprint(today time.Time, sprints int) (time.Time, bool) {
+	if r.SprintDays <= 0 {
+		return time.Time{}, false
+	}
+	start := today
+	if !r.SprintStart.IsZero() {
+		start = time.Date(r.SprintStart.Year(), r.SprintStart.Month(), r.SprintStart.Day(), 0, 0, 0, 0, today.Location())
+		days := int(today.Sub(start).Hours() / 24)
+		// 向下取整到锚点所在迭代的开始
+		k := days / r.SprintDays
+		if days < 0 && days%r.SprintDays != 0 {
+			k--
+		}
+		start = start.AddDate(0, 0, k*r.SprintDays)
+	}
+	return start.AddDate(0, 0, (sprints+1)*r.SprintDays-1), true
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func endOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 0, t.Location())
+}
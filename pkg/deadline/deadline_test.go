@@ -0,0 +1,83 @@
+package deadline
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	// 2025-05-07 周三
+	anchor := time.Date(2025, 5, 7, 10, 0, 0, 0, time.UTC)
+	r := &Resolver{SprintDays: 14}
+
+	tests := []struct {
+		text     string
+		want     string
+		dateOnly bool
+	}{
+		{"2025-06-01", "2025-06-01 23:59", true},
+		{"2025-06-01 18:00", "2025-06-01 18:00", false},
+		{"今天", "2025-05-07 23:59", true},
+		{"明天下午3点", "2025-05-08 15:00", false},
+		{"3天后", "2025-05-10 23:59", true},
+		{"三天内", "2025-05-10 23:59", true},
+		{"5个工作日内", "2025-05-14 23:59", true},
+		{"两个月后", "2025-07-07 23:59", true},
+		{"周五", "2025-05-09 23:59", true},
+		{"周五前", "2025-05-09 23:59", true},
+		{"下周一", "2025-05-12 23:59", true},
+		{"本周", "2025-05-09 23:59", true},
+		{"10号前", "2025-05-10 23:59", true},
+		{"10日前", "2025-05-10 23:59", true},
+		{"5号", "2025-06-05 23:59", true},
+		{"月底前", "2025-05-31 23:59", true},
+		{"6月底", "2025-06-30 23:59", true},
+		{"5月3日", "2026-05-03 23:59", true},
+		{"截止到5月20号", "2025-05-20 23:59", true},
+		{"迭代结束", "2025-05-20 23:59", true},
+		{"by friday", "2025-05-09 23:59", true},
+		{"next friday", "2025-05-16 23:59", true},
+		{"in 3 days", "2025-05-10 23:59", true},
+		{"end of month", "2025-05-31 23:59", true},
+		{"tomorrow 3pm", "2025-05-08 15:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			res, err := r.Resolve(tt.text, anchor)
+			if err != nil {
+				t.Fatalf("Resolve(%q) error: %v", tt.text, err)
+			}
+			if got := res.Time.Format("2006-01-02 15:04"); got != tt.want {
+				t.Errorf("Resolve(%q) = %s, want %s", tt.text, got, tt.want)
+			}
+			if res.DateOnly != tt.dateOnly {
+				t.Errorf("Resolve(%q).DateOnly = %v, want %v", tt.text, res.DateOnly, tt.dateOnly)
+			}
+		})
+	}
+}
+
+func TestResolveUnresolved(t *testing.T) {
+	anchor := time.Date(2025, 5, 7, 10, 0, 0, 0, time.UTC)
+	r := &Resolver{SprintDays: 14}
+
+	// 过去的时间不能被当作未来的截止时间
+	for _, text := range []string{
+		"",
+		"尽快",
+		"3天前",
+		"三天之前",
+		"两周以前",
+		"3个月前",
+		"一年前",
+		"3 days ago",
+	} {
+		t.Run(text, func(t *testing.T) {
+			res, err := r.Resolve(text, anchor)
+			if !errors.Is(err, ErrUnresolved) {
+				t.Fatalf("Resolve(%q) = %v, %v, want ErrUnresolved", text, res, err)
+			}
+		})
+	}
+}
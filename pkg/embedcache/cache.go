@@ -0,0 +1,276 @@
+package embedcache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/redis/go-redis/v9"
+
+	"meetingagent/pkg/env"
+)
+
+const (
+	DefaultPrefix = "eino:embcache:"
+
+	// modelMarkerKey 记录上一次使用的向量化模型，模型变化时清理旧模型的缓存
+	modelMarkerKey = "model"
+)
+
+// Stats 缓存命中统计，进程内所有 CachedEmbedder 共享
+type Stats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Errors int64 `json:"errors"`
+}
+
+var hits, misses, errs atomic.Int64
+
+// GetStats 返回进程启动以来的缓存命中统计
+func GetStats() Stats {
+	return Stats{
+		Hits:   hits.Load(),
+		Misses: misses.Load(),
+		Errors: errs.Load(),
+	}
+}
+
+type Config struct {
+	Client *redis.Client
+	// Model 向量化模型名称，作为缓存 key 的一部分
+	Model string
+	// Prefix 缓存 key 前缀，默认 DefaultPrefix
+	Prefix string
+	// TTL 缓存过期时间，0 表示不过期
+	TTL time.Duration
+}
+
+// CachedEmbedder 以 模型名 + 文本 sha256 为 key，将向量缓存在 Redis 中，
+// 相同文本在重复索引和重复查询时不再调用底层 Embedder
+type CachedEmbedder struct {
+	inner  embedding.Embedder
+	client *redis.Client
+	model  string
+	prefix string
+	ttl    time.Duration
+}
+
+func New(inner embedding.Embedder, config *Config) (*CachedEmbedder, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("embedder cannot be nil")
+	}
+	if config == nil || config.Client == nil {
+		return nil, fmt.Errorf("redis client cannot be nil")
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("model cannot be empty")
+	}
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	return &CachedEmbedder{
+		inner:  inner,
+		client: config.Client,
+		model:  config.Model,
+		prefix: prefix,
+		ttl:    config.TTL,
+	}, nil
+}
+
+var (
+	sharedOnce   sync.Once
+	sharedClient *redis.Client
+	sharedErr    error
+)
+
+// Wrap 按环境变量配置为 inner 套上缓存，EMBEDDING_CACHE_ENABLED=false 时原样返回。
+// knowledgeindexing 与 einoagent 共用同一个 Redis 连接和同一份缓存
+func Wrap(ctx context.Context, inner embedding.Embedder, model string) (embedding.Embedder, error) {
+	if !env.GetBool("EMBEDDING_CACHE_ENABLED", true) {
+		return inner, nil
+	}
+
+	sharedOnce.Do(func() {
+		sharedClient = redis.NewClient(&redis.Options{
+			Addr: env.GetString("REDIS_ADDR", "localhost:6379"),
+		})
+		// 模型变化时旧向量全部失效，这里顺便把它们清理掉
+		var removed int
+		removed, sharedErr = InvalidateStale(ctx, sharedClient, DefaultPrefix, model)
+		if removed > 0 {
+			log.Printf("[embedcache] model changed to %s, removed %d stale entries", model, removed)
+		}
+	})
+	if sharedErr != nil {
+		// Redis 不可用时不启用缓存，检索与索引照常工作
+		log.Printf("[embedcache] cache disabled: %v", sharedErr)
+		return inner, nil
+	}
+
+	return New(inner, &Config{
+		Client: sharedClient,
+		Model:  model,
+		TTL:    env.GetDuration("EMBEDDING_CACHE_TTL", 0),
+	})
+}
+
+func (e *CachedEmbedder) key(model, text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return e.prefix + model + ":" + hex.EncodeToString(sum[:])
+}
+
+func (e *CachedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	if len(texts) == 0 {
+		return [][]float64{}, nil
+	}
+
+	model := *embedding.GetCommonOptions(&embedding.Options{Model: &e.model}, opts...).Model
+
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		keys[i] = e.key(model, text)
+	}
+
+	vectors := make([][]float64, len(texts))
+	cached, err := e.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		// 缓存不可用时退化为直接调用底层 Embedder
+		errs.Add(1)
+		log.Printf("[embedcache] mget failed: %v", err)
+		cached = make([]interface{}, len(texts))
+	}
+
+	var missIdx []int
+	var missTexts []string
+	for i, v := range cached {
+		if s, ok := v.(string); ok {
+			if vec, err := decodeVector(s); err == nil {
+				vectors[i] = vec
+				continue
+			}
+		}
+		missIdx = append(missIdx, i)
+		missTexts = append(missTexts, texts[i])
+	}
+
+	hits.Add(int64(len(texts) - len(missIdx)))
+	misses.Add(int64(len(missIdx)))
+
+	if len(missTexts) == 0 {
+		return vectors, nil
+	}
+
+	embedded, err := e.inner.EmbedStrings(ctx, missTexts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missTexts) {
+		return nil, fmt.Errorf("[embedcache] invalid return length of vector, got=%d, expected=%d", len(embedded), len(missTexts))
+	}
+
+	pipe := e.client.Pipeline()
+	for j, i := range missIdx {
+		vectors[i] = embedded[j]
+		pipe.Set(ctx, keys[i], encodeVector(embedded[j]), e.ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		errs.Add(1)
+		log.Printf("[embedcache] write cache failed: %v", err)
+	}
+
+	return vectors, nil
+}
+
+// Invalidate 删除指定模型的全部缓存，返回删除的条目数
+func (e *CachedEmbedder) Invalidate(ctx context.Context, model string) (int, error) {
+	return deleteByPattern(ctx, e.client, e.prefix+model+":*", func(string) bool { return false })
+}
+
+// InvalidateStale 删除 prefix 下不属于 model 的缓存，并将 model 记为当前模型。
+// 记录的模型与 model 一致时不做任何事
+func InvalidateStale(ctx context.Context, client *redis.Client, prefix, model string) (int, error) {
+	marker := prefix + modelMarkerKey
+	last, err := client.Get(ctx, marker).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("failed to read embedding cache model marker: %w", err)
+	}
+	if last == model {
+		return 0, nil
+	}
+
+	removed, err := deleteByPattern(ctx, client, prefix+"*", func(key string) bool {
+		return key == marker || strings.HasPrefix(key, prefix+model+":")
+	})
+	if err != nil {
+		return removed, err
+	}
+	if err := client.Set(ctx, marker, model, 0).Err(); err != nil {
+		return removed, fmt.Errorf("failed to write embedding cache model marker: %w", err)
+	}
+	return removed, nil
+}
+
+// deleteByPattern 删除匹配 pattern 且 skip 返回 false 的 key
+func deleteByPattern(ctx context.Context, client *redis.Client, pattern string, skip func(key string) bool) (int, error) {
+	removed := 0
+	iter := client.Scan(ctx, 0, pattern, 500).Iterator()
+	batch := make([]string, 0, 500)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		n, err := client.Del(ctx, batch...).Result()
+		removed += int(n)
+		batch = batch[:0]
+		return err
+	}
+	for iter.Next(ctx) {
+		key := iter.Val()
+		if skip(key) {
+			continue
+		}
+		batch = append(batch, key)
+		if len(batch) == cap(batch) {
+			if err := flush(); err != nil {
+				return removed, fmt.Errorf("failed to delete cache entries: %w", err)
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return removed, fmt.Errorf("failed to scan cache entries: %w", err)
+	}
+	if err := flush(); err != nil {
+		return removed, fmt.Errorf("failed to delete cache entries: %w", err)
+	}
+	return removed, nil
+}
+
+func encodeVector(vec []float64) []byte {
+	buf := make([]byte, 8*len(vec))
+	for i, v := range vec {
+		binary.LittleEndian.PutUint64(buf[i*8:], math.Float64bits(v))
+	}
+	return buf
+}
+
+func decodeVector(s string) ([]float64, error) {
+	if len(s)%8 != 0 {
+		return nil, fmt.Errorf("invalid vector length: %d", len(s))
+	}
+	vec := make([]float64, len(s)/8)
+	for i := range vec {
+		vec[i] = math.Float64frombits(binary.LittleEndian.Uint64([]byte(s[i*8 : i*8+8])))
+	}
+	return vec, nil
+}
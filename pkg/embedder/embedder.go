@@ -0,0 +1,33 @@
+package embedder
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino-ext/components/embedding/ark"
+	"github.com/cloudwego/eino/components/embedding"
+
+	"meetingagent/pkg/embedcache"
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/ratelimit"
+)
+
+// New 按环境变量创建向量化模型（ARK_EMBEDDING_MODEL、ARK_API_KEY、ARK_BASE_URL），外面依次套上限流与缓存。
+// 检索、索引与任务查重都通过它创建，使用同一个接口地址与同一份缓存
+func New(ctx context.Context) (embedding.Embedder, error) {
+	config := &ark.EmbeddingConfig{
+		BaseURL: env.GetString("ARK_BASE_URL", "https://ark.cn-beijing.volces.com/api/v3"),
+		APIKey:  env.GetString("ARK_API_KEY", ""),
+		Model:   env.GetString("ARK_EMBEDDING_MODEL", ""),
+	}
+	var eb embedding.Embedder
+	eb, err := ark.NewEmbedder(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	// 限流放在缓存内侧，只有未命中缓存的文本才消耗配额
+	eb, err = ratelimit.WrapEmbedder(eb)
+	if err != nil {
+		return nil, err
+	}
+	return embedcache.Wrap(ctx, eb, config.Model)
+}
@@ -3,16 +3,23 @@ package env
 import (
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// init loads .env when present. Variables may also come from the process environment, e.g. in
+// containers or tests, so a missing file is not fatal; required variables are checked by MustHasEnvs.
 func init() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatalf("❌ [ERROR] Error loading .env file: %v", err)
+	if err := godotenv.Load(); err != nil {
+		log.Printf("⚠️ [WARN] .env file not loaded, using the process environment: %v", err)
 	}
+}
 
+// Reload re-reads the .env file, overriding variables that are already set.
+func Reload() error {
+	return godotenv.Overload()
 }
 
 func MustHasEnvs(envs ...string) {
@@ -22,3 +29,67 @@ func MustHasEnvs(envs ...string) {
 		}
 	}
 }
+
+// GetString returns the value of env, or def when it is not set.
+func GetString(env string, def string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return def
+}
+
+// GetInt returns env parsed as an int, or def when it is not set or invalid.
+func GetInt(env string, def int) int {
+	v := os.Getenv(env)
+	if v == "" {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("⚠️ [WARN] env [%s]=%q is not an int, fallback to %d", env, v, def)
+		return def
+	}
+	return i
+}
+
+// GetFloat returns env parsed as a float64, or def when it is not set or invalid.
+func GetFloat(env string, def float64) float64 {
+	v := os.Getenv(env)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("⚠️ [WARN] env [%s]=%q is not a float, fallback to %v", env, v, def)
+		return def
+	}
+	return f
+}
+
+// GetBool returns env parsed as a bool, or def when it is not set or invalid.
+func GetBool(env string, def bool) bool {
+	v := os.Getenv(env)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("⚠️ [WARN] env [%s]=%q is not a bool, fallback to %v", env, v, def)
+		return def
+	}
+	return b
+}
+
+// GetDuration returns env parsed as a time.Duration (e.g. "30s", "24h"), or def when it is not set or invalid.
+func GetDuration(env string, def time.Duration) time.Duration {
+	v := os.Getenv(env)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("⚠️ [WARN] env [%s]=%q is not a duration, fallback to %v", env, v, def)
+		return def
+	}
+	return d
+}
@@ -0,0 +1,230 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"meetingagent/pkg/env"
+)
+
+// Limits 一次对话的限制，0 表示不限制
+type Limits struct {
+	// MaxDuration 对话的总时长，不含等待用户确认的时间
+	MaxDuration time.Duration
+	// MaxTokens 所有模型调用消耗的 token 总数
+	MaxTokens int
+	// MaxToolCalls 工具调用总次数
+	MaxToolCalls int
+	// MaxModelCalls 模型调用次数，应小于 ReAct Agent 的 MaxStep，避免以错误结束
+	MaxModelCalls int
+	// MaxRepeatedCalls 以相同参数调用同一工具的次数，超过视为死循环
+	MaxRepeatedCalls int
+	// ToolTimeout 单次工具调用的超时，ToolTimeouts 可按工具名覆盖
+	ToolTimeout  time.Duration
+	ToolTimeouts map[string]time.Duration
+}
+
+// LimitsFromEnv 读取 AGENT_* 配置，maxStep 为 ReAct Agent 的 MaxStep，模型与工具交替执行，
+// 模型最多调用 (maxStep-1)/2 次
+func LimitsFromEnv(maxStep int) (*Limits, error) {
+	limits := &Limits{
+		MaxDuration:      env.GetDuration("AGENT_MAX_DURATION", 3*time.Minute),
+		MaxTokens:        env.GetInt("AGENT_MAX_TOKENS", 60000),
+		MaxToolCalls:     env.GetInt("AGENT_MAX_TOOL_CALLS", 15),
+		MaxModelCalls:    (maxStep - 1) / 2,
+		MaxRepeatedCalls: env.GetInt("AGENT_MAX_REPEATED_CALLS", 2),
+		ToolTimeout:      env.GetDuration("AGENT_TOOL_TIMEOUT", 30*time.Second),
+		ToolTimeouts:     make(map[string]time.Duration),
+	}
+	for _, item := range strings.Split(env.GetString("AGENT_TOOL_TIMEOUTS", ""), ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid tool timeout: %s", item)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tool timeout %s: %w", item, err)
+		}
+		limits.ToolTimeouts[strings.TrimSpace(name)] = timeout
+	}
+	return limits, nil
+}
+
+func (l *Limits) toolTimeout(name string) time.Duration {
+	if timeout, ok := l.ToolTimeouts[name]; ok {
+		return timeout
+	}
+	return l.ToolTimeout
+}
+
+// Reason 对话被中止的原因
+type Reason string
+
+const (
+	ReasonDuration   Reason = "duration"
+	ReasonTokens     Reason = "tokens"
+	ReasonToolCalls  Reason = "tool_calls"
+	ReasonModelCalls Reason = "model_calls"
+	ReasonLoop       Reason = "loop"
+)
+
+// Run 一次对话的用量，由 WithRun 放入 ctx，被同一对话中的模型与工具共享
+type Run struct {
+	mu         sync.Mutex
+	start      time.Time
+	paused     time.Duration
+	tokens     int
+	modelCalls int
+	toolCalls  int
+	calls      map[string]int
+	tools      []string
+
+	reason Reason
+	detail string
+}
+
+type runKey struct{}
+
+// WithRun 开始统计一次对话，ctx 中没有 Run 时只有工具超时生效
+func WithRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, runKey{}, &Run{start: time.Now(), calls: make(map[string]int)})
+}
+
+func runFrom(ctx context.Context) *Run {
+	r, _ := ctx.Value(runKey{}).(*Run)
+	return r
+}
+
+// Pause 暂停计时，例如等待用户确认时，返回的函数恢复计时
+func Pause(ctx context.Context) (resume func()) {
+	r := runFrom(ctx)
+	if r == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.paused += time.Since(start)
+	}
+}
+
+// Stopped 返回对话被中止的原因，未中止时为空
+func Stopped(ctx context.Context) Reason {
+	r := runFrom(ctx)
+	if r == nil {
+		return ""
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reason
+}
+
+// stop 记录第一个触发的限制，调用方需持有 mu
+func (r *Run) stop(reason Reason, detail string) {
+	if r.reason == "" {
+		r.reason, r.detail = reason, detail
+	}
+}
+
+// checkDuration 调用方需持有 mu
+func (r *Run) checkDuration(limits *Limits) {
+	if limits.MaxDuration > 0 && time.Since(r.start)-r.paused > limits.MaxDuration {
+		r.stop(ReasonDuration, fmt.Sprintf("处理时间超过了 %v 的限制", limits.MaxDuration))
+	}
+}
+
+// beforeModel 返回 false 时不再调用模型
+func (r *Run) beforeModel(limits *Limits) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkDuration(limits)
+	if limits.MaxTokens > 0 && r.tokens >= limits.MaxTokens {
+		r.stop(ReasonTokens, fmt.Sprintf("消耗的 token 超过了 %d 的限制", limits.MaxTokens))
+	}
+	if limits.MaxModelCalls > 0 && r.modelCalls >= limits.MaxModelCalls {
+		r.stop(ReasonModelCalls, fmt.Sprintf("推理步数超过了 %d 步的限制", limits.MaxModelCalls))
+	}
+	if r.reason != "" {
+		return false
+	}
+	r.modelCalls++
+	return true
+}
+
+func (r *Run) addTokens(tokens int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens += tokens
+}
+
+// beforeTool 返回 false 时不再执行工具，并中止对话
+func (r *Run) beforeTool(limits *Limits, name string, argumentsInJSON string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkDuration(limits)
+	if r.reason != "" {
+		return false
+	}
+
+	r.toolCalls++
+	if limits.MaxToolCalls > 0 && r.toolCalls > limits.MaxToolCalls {
+		r.stop(ReasonToolCalls, fmt.Sprintf("调用工具的次数超过了 %d 次的限制", limits.MaxToolCalls))
+		return false
+	}
+	key := name + "\x00" + canonicalJSON(argumentsInJSON)
+	r.calls[key]++
+	if limits.MaxRepeatedCalls > 0 && r.calls[key] > limits.MaxRepeatedCalls {
+		r.stop(ReasonLoop, fmt.Sprintf("在以相同的参数反复调用 %s", name))
+		return false
+	}
+	r.tools = append(r.tools, name)
+	return true
+}
+
+// summary 中止时返回给用户的说明
+func (r *Run) summary() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "抱歉，本次回答%s，已停止继续处理。", r.detail)
+	if len(r.tools) > 0 {
+		counts := make(map[string]int)
+		var names []string
+		for _, name := range r.tools {
+			if counts[name] == 0 {
+				names = append(names, name)
+			}
+			counts[name]++
+		}
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			parts = append(parts, fmt.Sprintf("%s %d 次", name, counts[name]))
+		}
+		fmt.Fprintf(&b, "期间调用了工具：%s。", strings.Join(parts, "、"))
+	}
+	b.WriteString("可以缩小问题范围或提供更具体的信息（例如会议日期、任务名称）后重试。")
+	return b.String()
+}
+
+// canonicalJSON 参数中的字段顺序与空白不影响重复调用的判断
+func canonicalJSON(s string) string {
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
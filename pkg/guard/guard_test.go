@@ -0,0 +1,133 @@
+package guard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// step 为空的 tool 表示一次模型调用，调用后消耗 tokens
+type step struct {
+	tool   string
+	args   string
+	tokens int
+}
+
+func TestRunLimits(t *testing.T) {
+	model := func(tokens int) step { return step{tokens: tokens} }
+	call := func(name, args string) step { return step{tool: name, args: args} }
+
+	tests := []struct {
+		name   string
+		limits Limits
+		steps  []step
+		// blocked 第一个被拒绝的步骤，-1 表示全部通过
+		blocked int
+		reason  Reason
+	}{
+		{
+			name:    "no limits",
+			steps:   []step{model(1000), call("search", `{}`), call("search", `{}`), model(1000)},
+			blocked: -1,
+		},
+		{
+			name:    "model calls",
+			limits:  Limits{MaxModelCalls: 2},
+			steps:   []step{model(0), call("search", `{"q":1}`), model(0), call("search", `{"q":2}`), model(0)},
+			blocked: 4,
+			reason:  ReasonModelCalls,
+		},
+		{
+			name:    "tokens checked before the next model call",
+			limits:  Limits{MaxTokens: 100},
+			steps:   []step{model(60), model(60), model(0)},
+			blocked: 2,
+			reason:  ReasonTokens,
+		},
+		{
+			name:    "tool calls",
+			limits:  Limits{MaxToolCalls: 2},
+			steps:   []step{call("a", `{}`), call("b", `{}`), call("c", `{}`)},
+			blocked: 2,
+			reason:  ReasonToolCalls,
+		},
+		{
+			name:    "repeated calls ignore key order and spaces",
+			limits:  Limits{MaxRepeatedCalls: 2},
+			steps:   []step{call("search", `{"a":1,"b":2}`), call("search", `{ "b": 2, "a": 1 }`), call("search", `{"a":1,"b":2}`)},
+			blocked: 2,
+			reason:  ReasonLoop,
+		},
+		{
+			name:    "different arguments are not a loop",
+			limits:  Limits{MaxRepeatedCalls: 1},
+			steps:   []step{call("search", `{"q":1}`), call("search", `{"q":2}`), call("get", `{"q":1}`)},
+			blocked: -1,
+		},
+		{
+			name:    "stopped run rejects later steps",
+			limits:  Limits{MaxToolCalls: 1},
+			steps:   []step{call("a", `{}`), call("b", `{}`), model(0)},
+			blocked: 1,
+			reason:  ReasonToolCalls,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithRun(context.Background())
+			r := runFrom(ctx)
+			blocked := -1
+			for i, s := range tt.steps {
+				var ok bool
+				if s.tool == "" {
+					if ok = r.beforeModel(&tt.limits); ok {
+						r.addTokens(s.tokens)
+					}
+				} else {
+					ok = r.beforeTool(&tt.limits, s.tool, s.args)
+				}
+				if !ok && blocked == -1 {
+					blocked = i
+				}
+				// 中止后的步骤都应被拒绝
+				if blocked != -1 && ok {
+					t.Fatalf("step %d passed after the run was stopped", i)
+				}
+			}
+			if blocked != tt.blocked {
+				t.Errorf("blocked at step %d, want %d", blocked, tt.blocked)
+			}
+			if got := Stopped(ctx); got != tt.reason {
+				t.Errorf("Stopped() = %q, want %q", got, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRunDurationExcludesPause(t *testing.T) {
+	limits := &Limits{MaxDuration: time.Minute}
+	ctx := WithRun(context.Background())
+	r := runFrom(ctx)
+
+	// 等待确认的时间不计入
+	r.start = time.Now().Add(-2 * time.Minute)
+	r.paused = 90 * time.Second
+	if !r.beforeModel(limits) {
+		t.Fatalf("model call rejected, reason %q", Stopped(ctx))
+	}
+
+	r.paused = 0
+	if r.beforeTool(limits, "search", `{}`) {
+		t.Fatal("tool call passed after the duration limit")
+	}
+	if got := Stopped(ctx); got != ReasonDuration {
+		t.Errorf("Stopped() = %q, want %q", got, ReasonDuration)
+	}
+}
+
+func TestStoppedWithoutRun(t *testing.T) {
+	if got := Stopped(context.Background()); got != "" {
+		t.Errorf("Stopped() = %q, want empty", got)
+	}
+	Pause(context.Background())()
+}
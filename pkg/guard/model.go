@@ -0,0 +1,74 @@
+package guard
+
+import (
+	"context"
+	"log"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// WrapModel 在每次模型调用前检查限制，超过限制时不再调用模型，而是直接返回说明，
+// ReAct Agent 收到没有工具调用的消息后正常结束
+func WrapModel(cm model.ChatModel, limits *Limits) model.ChatModel {
+	return &guardedModel{inner: cm, limits: limits}
+}
+
+type guardedModel struct {
+	inner  model.ChatModel
+	limits *Limits
+}
+
+func (m *guardedModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	run := runFrom(ctx)
+	if run != nil && !run.beforeModel(m.limits) {
+		return stoppedMessage(run), nil
+	}
+	msg, err := m.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if run != nil && msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		run.addTokens(msg.ResponseMeta.Usage.TotalTokens)
+	}
+	return msg, nil
+}
+
+func (m *guardedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	run := runFrom(ctx)
+	if run != nil && !run.beforeModel(m.limits) {
+		return schema.StreamReaderFromArray([]*schema.Message{stoppedMessage(run)}), nil
+	}
+	sr, err := m.inner.Stream(ctx, input, opts...)
+	if err != nil || run == nil {
+		return sr, err
+	}
+	// 流式输出的用量在最后一个 chunk 中
+	return schema.StreamReaderWithConvert(sr, func(msg *schema.Message) (*schema.Message, error) {
+		if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+			run.addTokens(msg.ResponseMeta.Usage.TotalTokens)
+		}
+		return msg, nil
+	}), nil
+}
+
+func (m *guardedModel) BindTools(tools []*schema.ToolInfo) error {
+	return m.inner.BindTools(tools)
+}
+
+// IsCallbacksEnabled 回调由被包装的模型触发，避免同一次调用触发两次回调
+func (m *guardedModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func stoppedMessage(run *Run) *schema.Message {
+	content := run.summary()
+	run.mu.Lock()
+	reason := run.reason
+	run.mu.Unlock()
+	log.Printf("[guard] run stopped: %s", reason)
+
+	msg := schema.AssistantMessage(content, nil)
+	msg.Extra = map[string]any{"guardrail": string(reason)}
+	return msg
+}
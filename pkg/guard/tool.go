@@ -0,0 +1,84 @@
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// WrapTools 为工具加上超时、调用次数与重复调用的限制。超时或超过限制时返回说明给模型，而不是返回错误
+func WrapTools(ctx context.Context, tools []tool.BaseTool, limits *Limits) ([]tool.BaseTool, error) {
+	wrapped := make([]tool.BaseTool, 0, len(tools))
+	for _, t := range tools {
+		it, ok := t.(tool.InvokableTool)
+		if !ok {
+			wrapped = append(wrapped, t)
+			continue
+		}
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		wrapped = append(wrapped, &guardedTool{inner: it, name: info.Name, limits: limits})
+	}
+	return wrapped, nil
+}
+
+type guardedTool struct {
+	inner  tool.InvokableTool
+	name   string
+	limits *Limits
+}
+
+func (t *guardedTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return t.inner.Info(ctx)
+}
+
+func (t *guardedTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	if run := runFrom(ctx); run != nil && !run.beforeTool(t.limits, t.name, argumentsInJSON) {
+		run.mu.Lock()
+		detail := run.detail
+		run.mu.Unlock()
+		return result("stopped", "本次回答"+detail+"，工具未执行，请根据已有信息回答用户"), nil
+	}
+
+	timeout := t.limits.toolTimeout(t.name)
+	if timeout <= 0 {
+		return t.inner.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+	toolCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// 工具不响应 ctx 时也能按时返回
+	type output struct {
+		out string
+		err error
+	}
+	done := make(chan output, 1)
+	go func() {
+		out, err := t.inner.InvokableRun(toolCtx, argumentsInJSON, opts...)
+		done <- output{out, err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err == nil || ctx.Err() != nil || !errors.Is(toolCtx.Err(), context.DeadlineExceeded) {
+			return o.out, o.err
+		}
+	case <-toolCtx.Done():
+		// 对话本身被取消时仍返回错误
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+	}
+	return result("timeout", fmt.Sprintf("工具 %s 在 %v 内没有返回，可以稍后重试或换一种方式回答", t.name, timeout)), nil
+}
+
+func result(status string, message string) string {
+	b, _ := json.Marshal(map[string]string{"status": status, "message": message})
+	return string(b)
+}
@@ -0,0 +1,158 @@
+package mem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+
+	"meetingagent/pkg/env"
+)
+
+// Memory stores the conversations of each session.
+type Memory interface {
+	GetConversation(id string, createIfNotExist bool) Conversation
+	HasConversation(id string) bool
+	ListConversations() []string
+	DeleteConversation(id string) error
+}
+
+// SessionInfo is the metadata of a conversation.
+type SessionInfo struct {
+	ID        string            `json:"id"`
+	MeetingID string            `json:"meeting_id,omitempty"`
+	Title     string            `json:"title"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+	Messages  int               `json:"messages"`
+}
+
+// Conversation is the message history of one session.
+type Conversation interface {
+	Append(msg *schema.Message)
+	// GetFullMessages returns all messages of the conversation.
+	GetFullMessages() []*schema.Message
+	// GetMessages returns the messages within the window, preceded by the summary of older messages if any.
+	GetMessages() []*schema.Message
+	// PopLastTurn removes the last user message and everything after it, and returns the removed user message.
+	PopLastTurn() (*schema.Message, bool)
+	// Compact folds messages that fell out of the window into the summary.
+	Compact(ctx context.Context) error
+	// Info returns the metadata, the title defaults to the beginning of the first question.
+	Info() SessionInfo
+	// UpdateInfo changes MeetingID, Title and Metadata, other fields are ignored.
+	UpdateInfo(update func(info *SessionInfo)) error
+}
+
+// NewFromEnv creates the memory selected by env:
+//   - MEMORY_STORE: file (default) or redis
+//   - MEMORY_DIR: directory of the file memory, default data/memory
+//   - MEMORY_REDIS_TTL: expiration of idle conversations in redis, default 168h, 0 means never
+//   - MEMORY_WINDOW_SIZE / MEMORY_TOKEN_BUDGET: size of the history window, default 6 messages and 2000 tokens
+func NewFromEnv(summarizer Summarizer) (Memory, error) {
+	window := windowConfig{
+		maxWindowSize: env.GetInt("MEMORY_WINDOW_SIZE", 6),
+		tokenBudget:   env.GetInt("MEMORY_TOKEN_BUDGET", 2000),
+		summarizer:    summarizer,
+	}
+
+	switch kind := env.GetString("MEMORY_STORE", "file"); kind {
+	case "file":
+		m := NewSimpleMemory(SimpleMemoryConfig{
+			Dir:           env.GetString("MEMORY_DIR", "data/memory"),
+			MaxWindowSize: window.maxWindowSize,
+			TokenBudget:   window.tokenBudget,
+			Summarizer:    window.summarizer,
+		})
+		if m == nil {
+			return nil, fmt.Errorf("failed to create memory dir")
+		}
+		return m, nil
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr: env.GetString("REDIS_ADDR", "localhost:6379"),
+		})
+		return NewRedisMemory(RedisMemoryConfig{
+			Client:        client,
+			TTL:           env.GetDuration("MEMORY_REDIS_TTL", 7*24*time.Hour),
+			MaxWindowSize: window.maxWindowSize,
+			TokenBudget:   window.tokenBudget,
+			Summarizer:    window.summarizer,
+		})
+	default:
+		return nil, fmt.Errorf("unknown memory store: %s", kind)
+	}
+}
+
+// windowConfig decides which messages are kept verbatim, shared by the implementations.
+type windowConfig struct {
+	maxWindowSize int
+	tokenBudget   int
+	summarizer    Summarizer
+}
+
+// windowStart returns the index of the first message kept in the window. The window holds at most
+// maxWindowSize messages within tokenBudget, and starts with a user message so that turns are not split.
+func (w *windowConfig) windowStart(msgs []*schema.Message) int {
+	start := len(msgs)
+	tokens := 0
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if w.maxWindowSize > 0 && len(msgs)-i > w.maxWindowSize {
+			break
+		}
+		tokens += estimateTokens(msgs[i])
+		if w.tokenBudget > 0 && tokens > w.tokenBudget && start < len(msgs) {
+			break
+		}
+		start = i
+	}
+	for start < len(msgs) && msgs[start].Role != schema.User {
+		start++
+	}
+	return start
+}
+
+// history returns the window preceded by the summary, which covers msgs[:summarized].
+func (w *windowConfig) history(msgs []*schema.Message, summary string, summarized int) []*schema.Message {
+	start := w.windowStart(msgs)
+	if summary == "" {
+		return msgs[start:]
+	}
+
+	start = max(start, summarized)
+	history := make([]*schema.Message, 0, len(msgs)-start+1)
+	history = append(history, summaryMessage(summary))
+	return append(history, msgs[start:]...)
+}
+
+// lastUserIndex returns the index of the last user message, or -1.
+func lastUserIndex(msgs []*schema.Message) int {
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == schema.User {
+			return i
+		}
+	}
+	return -1
+}
+
+// titleRunes is the length of the default session title.
+const titleRunes = 30
+
+// defaultTitle returns the beginning of the first user message.
+func defaultTitle(msgs []*schema.Message) string {
+	for _, msg := range msgs {
+		if msg.Role != schema.User {
+			continue
+		}
+		title := []rune(strings.TrimSpace(msg.Content))
+		if len(title) > titleRunes {
+			return string(title[:titleRunes]) + "…"
+		}
+		return string(title)
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package mem
+
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// conversation has three turns, the second one calls a tool. Empty messages count as 4 tokens each.
+func conversation() []*schema.Message {
+	return []*schema.Message{
+		schema.UserMessage(""),
+		schema.AssistantMessage("", nil),
+		schema.UserMessage(""),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "call"}}),
+		schema.ToolMessage("", "call"),
+		schema.AssistantMessage("", nil),
+		schema.UserMessage(""),
+		schema.AssistantMessage("", nil),
+	}
+}
+
+func TestWindowStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		window windowConfig
+		msgs   []*schema.Message
+		want   int
+	}{
+		{name: "no limits", msgs: conversation(), want: 0},
+		{name: "size cuts inside a turn", window: windowConfig{maxWindowSize: 5}, msgs: conversation(), want: 6},
+		{name: "size on a turn boundary", window: windowConfig{maxWindowSize: 6}, msgs: conversation(), want: 2},
+		{name: "token budget", window: windowConfig{tokenBudget: 12}, msgs: conversation(), want: 6},
+		{name: "smaller limit wins", window: windowConfig{maxWindowSize: 6, tokenBudget: 12}, msgs: conversation(), want: 6},
+		{name: "last message over budget is kept", window: windowConfig{tokenBudget: 1}, msgs: conversation()[:3], want: 2},
+		{name: "no user message in window", window: windowConfig{maxWindowSize: 1}, msgs: conversation(), want: 8},
+		{name: "empty", window: windowConfig{maxWindowSize: 3}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.windowStart(tt.msgs); got != tt.want {
+				t.Errorf("windowStart() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	msgs := conversation()
+	tests := []struct {
+		name       string
+		window     windowConfig
+		summary    string
+		summarized int
+		// want lists indexes into msgs, -1 stands for the summary
+		want []int
+	}{
+		{name: "no summary", window: windowConfig{maxWindowSize: 6}, want: []int{2, 3, 4, 5, 6, 7}},
+		{name: "summary before window", window: windowConfig{maxWindowSize: 2}, summary: "s", summarized: 2, want: []int{-1, 6, 7}},
+		{name: "summary overlaps window", window: windowConfig{maxWindowSize: 6}, summary: "s", summarized: 6, want: []int{-1, 6, 7}},
+		{name: "summary without limits", summary: "s", summarized: 2, want: []int{-1, 2, 3, 4, 5, 6, 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.window.history(msgs, tt.summary, tt.summarized)
+			if len(got) != len(tt.want) {
+				t.Fatalf("history() returned %d messages, want %d", len(got), len(tt.want))
+			}
+			for i, idx := range tt.want {
+				if idx == -1 {
+					if got[i].Role != schema.System || got[i].Content != summaryMessage(tt.summary).Content {
+						t.Errorf("history()[%d] = %+v, want the summary", i, got[i])
+					}
+					continue
+				}
+				if got[i] != msgs[idx] {
+					t.Errorf("history()[%d] is not msgs[%d]", i, idx)
+				}
+			}
+		})
+	}
+}
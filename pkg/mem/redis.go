@@ -0,0 +1,344 @@
+package mem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix      = "eino:memory:"
+	redisMessagesSuffix = ":messages"
+	redisSummarySuffix  = ":summary"
+	redisInfoSuffix     = ":info"
+)
+
+type RedisMemoryConfig struct {
+	Client *redis.Client
+	// TTL expires conversations that have not been written for this long, 0 means never.
+	TTL           time.Duration
+	MaxWindowSize int
+	TokenBudget   int
+	Summarizer    Summarizer
+}
+
+// RedisMemory stores each conversation as a redis list of json messages, so that
+// every server instance sees the same history.
+type RedisMemory struct {
+	client *redis.Client
+	ttl    time.Duration
+	window windowConfig
+}
+
+func NewRedisMemory(cfg RedisMemoryConfig) (*RedisMemory, error) {
+	if cfg.Client == nil {
+		return nil, fmt.Errorf("redis client is required")
+	}
+	if err := cfg.Client.Ping(context.Background()).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &RedisMemory{
+		client: cfg.Client,
+		ttl:    cfg.TTL,
+		window: windowConfig{
+			maxWindowSize: cfg.MaxWindowSize,
+			tokenBudget:   cfg.TokenBudget,
+			summarizer:    cfg.Summarizer,
+		},
+	}, nil
+}
+
+// GetConversation does not touch redis, a conversation exists once a message is appended.
+func (m *RedisMemory) GetConversation(id string, createIfNotExist bool) Conversation {
+	return &RedisConversation{
+		ID:          id,
+		memory:      m,
+		messagesKey: redisKeyPrefix + id + redisMessagesSuffix,
+		summaryKey:  redisKeyPrefix + id + redisSummarySuffix,
+		infoKey:     redisKeyPrefix + id + redisInfoSuffix,
+	}
+}
+
+func (m *RedisMemory) HasConversation(id string) bool {
+	n, err := m.client.Exists(context.Background(), redisKeyPrefix+id+redisMessagesSuffix).Result()
+	if err != nil {
+		log.Printf("[memory] failed to check conversation %s: %v", id, err)
+	}
+	return n > 0
+}
+
+func (m *RedisMemory) ListConversations() []string {
+	ctx := context.Background()
+	ids := make([]string, 0)
+	iter := m.client.Scan(ctx, 0, redisKeyPrefix+"*"+redisMessagesSuffix, 200).Iterator()
+	for iter.Next(ctx) {
+		id := strings.TrimPrefix(iter.Val(), redisKeyPrefix)
+		ids = append(ids, strings.TrimSuffix(id, redisMessagesSuffix))
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("[memory] failed to list conversations: %v", err)
+	}
+	return ids
+}
+
+func (m *RedisMemory) DeleteConversation(id string) error {
+	err := m.client.Del(context.Background(),
+		redisKeyPrefix+id+redisMessagesSuffix,
+		redisKeyPrefix+id+redisSummarySuffix,
+		redisKeyPrefix+id+redisInfoSuffix,
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	return nil
+}
+
+// RedisConversation reads through to redis on every call, nothing is cached in the process.
+type RedisConversation struct {
+	ID string
+
+	memory      *RedisMemory
+	messagesKey string
+	summaryKey  string
+	infoKey     string
+}
+
+func (c *RedisConversation) Append(msg *schema.Message) {
+	ctx := context.Background()
+	str, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("[memory] failed to marshal message: %v", err)
+		return
+	}
+
+	now := time.Now().Format(time.RFC3339Nano)
+	_, err = c.memory.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.RPush(ctx, c.messagesKey, str)
+		pipe.HSetNX(ctx, c.infoKey, "created_at", now)
+		pipe.HSet(ctx, c.infoKey, "updated_at", now)
+		c.expire(ctx, pipe)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[memory] failed to append message to %s: %v", c.ID, err)
+	}
+}
+
+func (c *RedisConversation) GetFullMessages() []*schema.Message {
+	msgs, err := c.load(context.Background())
+	if err != nil {
+		log.Printf("[memory] failed to load conversation %s: %v", c.ID, err)
+	}
+	return msgs
+}
+
+func (c *RedisConversation) GetMessages() []*schema.Message {
+	ctx := context.Background()
+	msgs, err := c.load(ctx)
+	if err != nil {
+		log.Printf("[memory] failed to load conversation %s: %v", c.ID, err)
+		return msgs
+	}
+	summary, summarized, err := c.loadSummary(ctx)
+	if err != nil {
+		log.Printf("[memory] failed to load summary of %s: %v", c.ID, err)
+	}
+	return c.memory.window.history(msgs, summary, min(summarized, len(msgs)))
+}
+
+func (c *RedisConversation) PopLastTurn() (*schema.Message, bool) {
+	ctx := context.Background()
+	msgs, err := c.load(ctx)
+	if err != nil {
+		log.Printf("[memory] failed to load conversation %s: %v", c.ID, err)
+		return nil, false
+	}
+	i := lastUserIndex(msgs)
+	if i < 0 {
+		return nil, false
+	}
+
+	// the summary never covers the window, but may after messages are removed
+	_, summarized, _ := c.loadSummary(ctx)
+
+	_, err = c.memory.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if i == 0 {
+			pipe.Del(ctx, c.messagesKey)
+		} else {
+			pipe.LTrim(ctx, c.messagesKey, 0, int64(i-1))
+		}
+		if summarized > i {
+			pipe.HSet(ctx, c.summaryKey, "summarized", i)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[memory] failed to remove last turn of %s: %v", c.ID, err)
+		return nil, false
+	}
+	return msgs[i], true
+}
+
+// Compact may run on several instances at once, the summary is only written when nobody else
+// changed it while summarizing.
+func (c *RedisConversation) Compact(ctx context.Context) error {
+	if c.memory.window.summarizer == nil {
+		return nil
+	}
+
+	msgs, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	summary, from, err := c.loadSummary(ctx)
+	if err != nil {
+		return err
+	}
+	start := c.memory.window.windowStart(msgs)
+	if start <= from {
+		return nil
+	}
+
+	newSummary, err := c.memory.window.summarizer.Summarize(ctx, summary, msgs[from:start])
+	if err != nil {
+		return err
+	}
+
+	err = c.memory.client.Watch(ctx, func(tx *redis.Tx) error {
+		_, current, err := c.loadSummaryWith(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if current != from {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, c.summaryKey, "summary", newSummary, "summarized", start)
+			c.expire(ctx, pipe)
+			return nil
+		})
+		return err
+	}, c.summaryKey)
+	if errors.Is(err, redis.TxFailedErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+	return nil
+}
+
+func (c *RedisConversation) Info() SessionInfo {
+	ctx := context.Background()
+	info, err := c.loadInfo(ctx)
+	if err != nil {
+		log.Printf("[memory] failed to load info of %s: %v", c.ID, err)
+	}
+
+	pipe := c.memory.client.Pipeline()
+	count := pipe.LLen(ctx, c.messagesKey)
+	first := pipe.LRange(ctx, c.messagesKey, 0, 1)
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("[memory] failed to load conversation %s: %v", c.ID, err)
+	}
+	info.Messages = int(count.Val())
+	if info.Title == "" {
+		var msgs []*schema.Message
+		for _, v := range first.Val() {
+			var msg schema.Message
+			if err := json.Unmarshal([]byte(v), &msg); err == nil {
+				msgs = append(msgs, &msg)
+			}
+		}
+		info.Title = defaultTitle(msgs)
+	}
+	return info
+}
+
+func (c *RedisConversation) UpdateInfo(update func(info *SessionInfo)) error {
+	ctx := context.Background()
+	info, err := c.loadInfo(ctx)
+	if err != nil {
+		return err
+	}
+	update(&info)
+
+	metadata, err := json.Marshal(info.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	now := time.Now().Format(time.RFC3339Nano)
+	_, err = c.memory.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, c.infoKey, "meeting_id", info.MeetingID, "title", info.Title, "metadata", metadata)
+		pipe.HSetNX(ctx, c.infoKey, "created_at", now)
+		pipe.HSetNX(ctx, c.infoKey, "updated_at", now)
+		c.expire(ctx, pipe)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save info: %w", err)
+	}
+	return nil
+}
+
+func (c *RedisConversation) loadInfo(ctx context.Context) (SessionInfo, error) {
+	info := SessionInfo{ID: c.ID}
+	values, err := c.memory.client.HGetAll(ctx, c.infoKey).Result()
+	if err != nil {
+		return info, fmt.Errorf("failed to read info: %w", err)
+	}
+	info.MeetingID = values["meeting_id"]
+	info.Title = values["title"]
+	if v := values["metadata"]; v != "" {
+		_ = json.Unmarshal([]byte(v), &info.Metadata)
+	}
+	info.CreatedAt, _ = time.Parse(time.RFC3339Nano, values["created_at"])
+	info.UpdatedAt, _ = time.Parse(time.RFC3339Nano, values["updated_at"])
+	return info, nil
+}
+
+func (c *RedisConversation) load(ctx context.Context) ([]*schema.Message, error) {
+	values, err := c.memory.client.LRange(ctx, c.messagesKey, 0, -1).Result()
+	if err != nil {
+		return make([]*schema.Message, 0), fmt.Errorf("failed to read messages: %w", err)
+	}
+	msgs := make([]*schema.Message, 0, len(values))
+	for _, v := range values {
+		var msg schema.Message
+		if err := json.Unmarshal([]byte(v), &msg); err != nil {
+			return msgs, fmt.Errorf("failed to unmarshal message: %w", err)
+		}
+		msgs = append(msgs, &msg)
+	}
+	return msgs, nil
+}
+
+func (c *RedisConversation) loadSummary(ctx context.Context) (string, int, error) {
+	return c.loadSummaryWith(ctx, c.memory.client)
+}
+
+func (c *RedisConversation) loadSummaryWith(ctx context.Context, cmd redis.Cmdable) (string, int, error) {
+	values, err := cmd.HGetAll(ctx, c.summaryKey).Result()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to read summary: %w", err)
+	}
+	summarized, _ := strconv.Atoi(values["summarized"])
+	return values["summary"], summarized, nil
+}
+
+func (c *RedisConversation) expire(ctx context.Context, pipe redis.Pipeliner) {
+	if c.memory.ttl <= 0 {
+		return
+	}
+	pipe.Expire(ctx, c.messagesKey, c.memory.ttl)
+	pipe.Expire(ctx, c.summaryKey, c.memory.ttl)
+	pipe.Expire(ctx, c.infoKey, c.memory.ttl)
+}
@@ -2,12 +2,15 @@ package mem
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudwego/eino/schema"
 )
@@ -22,6 +25,11 @@ func GetDefaultMemory() *SimpleMemory {
 type SimpleMemoryConfig struct {
 	Dir           string
 	MaxWindowSize int
+	// TokenBudget limits the estimated tokens of the history window, 0 means no limit.
+	TokenBudget int
+	// Summarizer folds messages evicted from the window into a running summary,
+	// nil means evicted messages are dropped.
+	Summarizer Summarizer
 }
 
 func NewSimpleMemory(cfg SimpleMemoryConfig) *SimpleMemory {
@@ -33,9 +41,13 @@ func NewSimpleMemory(cfg SimpleMemoryConfig) *SimpleMemory {
 	}
 
 	return &SimpleMemory{
-		dir:           cfg.Dir,
-		maxWindowSize: cfg.MaxWindowSize,
-		conversations: make(map[string]*Conversation),
+		dir: cfg.Dir,
+		window: windowConfig{
+			maxWindowSize: cfg.MaxWindowSize,
+			tokenBudget:   cfg.TokenBudget,
+			summarizer:    cfg.Summarizer,
+		},
+		conversations: make(map[string]*FileConversation),
 	}
 }
 
@@ -43,11 +55,11 @@ func NewSimpleMemory(cfg SimpleMemoryConfig) *SimpleMemory {
 type SimpleMemory struct {
 	mu            sync.Mutex
 	dir           string
-	maxWindowSize int
-	conversations map[string]*Conversation
+	window        windowConfig
+	conversations map[string]*FileConversation
 }
 
-func (m *SimpleMemory) GetConversation(id string, createIfNotExist bool) *Conversation {
+func (m *SimpleMemory) GetConversation(id string, createIfNotExist bool) Conversation {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -60,28 +72,29 @@ func (m *SimpleMemory) GetConversation(id string, createIfNotExist bool) *Conver
 				if err := os.WriteFile(filePath, []byte(""), 0644); err != nil {
 					return nil
 				}
-				m.conversations[id] = &Conversation{
-					ID:            id,
-					Messages:      make([]*schema.Message, 0),
-					filePath:      filePath,
-					maxWindowSize: m.maxWindowSize,
-				}
 			}
 		}
 
-		con := &Conversation{
-			ID:            id,
-			Messages:      make([]*schema.Message, 0),
-			filePath:      filePath,
-			maxWindowSize: m.maxWindowSize,
+		con := &FileConversation{
+			ID:       id,
+			Messages: make([]*schema.Message, 0),
+			filePath: filePath,
+			window:   &m.window,
 		}
 		con.load()
+		con.loadSummary()
+		con.loadInfo()
 		m.conversations[id] = con
 	}
 
 	return m.conversations[id]
 }
 
+func (m *SimpleMemory) HasConversation(id string) bool {
+	_, err := os.Stat(filepath.Join(m.dir, id+".jsonl"))
+	return err == nil
+}
+
 func (m *SimpleMemory) ListConversations() []string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -93,7 +106,7 @@ func (m *SimpleMemory) ListConversations() []string {
 
 	ids := make([]string, 0, len(files))
 	for _, file := range files {
-		if file.IsDir() {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".jsonl") {
 			continue
 		}
 		ids = append(ids, strings.TrimSuffix(file.Name(), ".jsonl"))
@@ -110,51 +123,141 @@ func (m *SimpleMemory) DeleteConversation(id string) error {
 	if err := os.Remove(filePath); err != nil {
 		return fmt.Errorf("failed to delete file: %w", err)
 	}
+	_ = os.Remove(summaryPath(filePath))
+	_ = os.Remove(infoPath(filePath))
 
 	delete(m.conversations, id)
 	return nil
 }
 
-type Conversation struct {
+// FileConversation stores messages in a jsonl file, one message per line.
+type FileConversation struct {
 	mu sync.Mutex
 
 	ID       string            `json:"id"`
 	Messages []*schema.Message `json:"messages"`
+	// Summary covers Messages[:Summarized], it is persisted next to the messages.
+	Summary    string `json:"summary,omitempty"`
+	Summarized int    `json:"summarized,omitempty"`
 
-	filePath string
+	// info is persisted next to the messages, Title and Messages are filled in by Info
+	info SessionInfo
 
-	maxWindowSize int
+	filePath string
+	window   *windowConfig
 }
 
-func (c *Conversation) Append(msg *schema.Message) {
+func (c *FileConversation) Append(msg *schema.Message) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	c.Messages = append(c.Messages, msg)
 
 	c.save(msg)
+
+	c.info.UpdatedAt = time.Now()
+	if c.info.CreatedAt.IsZero() {
+		c.info.CreatedAt = c.info.UpdatedAt
+	}
+	if err := c.saveInfo(); err != nil {
+		fmt.Println("error saving conversation info: ", err.Error())
+	}
 }
 
-func (c *Conversation) GetFullMessages() []*schema.Message {
+func (c *FileConversation) Info() SessionInfo {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	return c.Messages
+	info := c.info
+	info.ID = c.ID
+	info.Messages = len(c.Messages)
+	if info.Title == "" {
+		info.Title = defaultTitle(c.Messages)
+	}
+	return info
 }
 
-// get messages with max window size
-func (c *Conversation) GetMessages() []*schema.Message {
+func (c *FileConversation) UpdateInfo(update func(info *SessionInfo)) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if len(c.Messages) > c.maxWindowSize {
-		return c.Messages[len(c.Messages)-c.maxWindowSize:]
+	info := c.info
+	info.Metadata = maps.Clone(c.info.Metadata)
+	update(&info)
+	c.info.MeetingID, c.info.Title, c.info.Metadata = info.MeetingID, info.Title, info.Metadata
+	if c.info.CreatedAt.IsZero() {
+		c.info.CreatedAt = time.Now()
+		c.info.UpdatedAt = c.info.CreatedAt
 	}
+	return c.saveInfo()
+}
+
+func (c *FileConversation) GetFullMessages() []*schema.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	return c.Messages
 }
 
-func (c *Conversation) load() error {
+func (c *FileConversation) GetMessages() []*schema.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.window.history(c.Messages, c.Summary, c.Summarized)
+}
+
+// Compact calls the summarizer, it should be called after a turn is appended, outside of the request path.
+func (c *FileConversation) Compact(ctx context.Context) error {
+	if c.window.summarizer == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	start := c.window.windowStart(c.Messages)
+	if start <= c.Summarized {
+		c.mu.Unlock()
+		return nil
+	}
+	from, summary := c.Summarized, c.Summary
+	evicted := append([]*schema.Message(nil), c.Messages[from:start]...)
+	c.mu.Unlock()
+
+	newSummary, err := c.window.summarizer.Summarize(ctx, summary, evicted)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// messages may have been removed while summarizing
+	if c.Summarized != from || start > len(c.Messages) {
+		return nil
+	}
+	c.Summary, c.Summarized = newSummary, start
+	return c.saveSummary()
+}
+
+func (c *FileConversation) PopLastTurn() (*schema.Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := lastUserIndex(c.Messages)
+	if i < 0 {
+		return nil, false
+	}
+	user := c.Messages[i]
+	c.Messages = c.Messages[:i]
+	if c.Summarized > len(c.Messages) {
+		c.Summarized = len(c.Messages)
+		_ = c.saveSummary()
+	}
+	if err := c.rewrite(); err != nil {
+		fmt.Println("error rewriting conversation: ", err.Error())
+	}
+	return user, true
+}
+
+func (c *FileConversation) load() error {
 	reader, err := os.Open(c.filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
@@ -178,7 +281,7 @@ func (c *Conversation) load() error {
 	return nil
 }
 
-func (c *Conversation) save(msg *schema.Message) {
+func (c *FileConversation) save(msg *schema.Message) {
 	str, _ := json.Marshal(msg)
 
 	// Append to file
@@ -190,3 +293,85 @@ func (c *Conversation) save(msg *schema.Message) {
 	f.Write(str)
 	f.WriteString("\n")
 }
+
+// rewrite overwrites the file with the current messages.
+func (c *FileConversation) rewrite() error {
+	var buf strings.Builder
+	for _, msg := range c.Messages {
+		str, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message: %w", err)
+		}
+		buf.Write(str)
+		buf.WriteString("\n")
+	}
+
+	tmp := c.filePath + ".tmp"
+	if err := os.WriteFile(tmp, []byte(buf.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return os.Rename(tmp, c.filePath)
+}
+
+func summaryPath(filePath string) string {
+	return strings.TrimSuffix(filePath, ".jsonl") + ".summary.json"
+}
+
+type summaryFile struct {
+	Summary    string `json:"summary"`
+	Summarized int    `json:"summarized"`
+}
+
+func (c *FileConversation) loadSummary() {
+	data, err := os.ReadFile(summaryPath(c.filePath))
+	if err != nil {
+		return
+	}
+	var f summaryFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return
+	}
+	c.Summary = f.Summary
+	c.Summarized = min(f.Summarized, len(c.Messages))
+}
+
+func (c *FileConversation) saveSummary() error {
+	data, err := json.Marshal(summaryFile{Summary: c.Summary, Summarized: c.Summarized})
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary: %w", err)
+	}
+	if err := os.WriteFile(summaryPath(c.filePath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
+	return nil
+}
+
+func infoPath(filePath string) string {
+	return strings.TrimSuffix(filePath, ".jsonl") + ".info.json"
+}
+
+// loadInfo falls back to the modification time of the messages file for conversations created
+// before the info file existed.
+func (c *FileConversation) loadInfo() {
+	data, err := os.ReadFile(infoPath(c.filePath))
+	if err == nil {
+		_ = json.Unmarshal(data, &c.info)
+	}
+	if c.info.CreatedAt.IsZero() {
+		if stat, err := os.Stat(c.filePath); err == nil && len(c.Messages) > 0 {
+			c.info.CreatedAt = stat.ModTime()
+			c.info.UpdatedAt = stat.ModTime()
+		}
+	}
+}
+
+func (c *FileConversation) saveInfo() error {
+	data, err := json.Marshal(c.info)
+	if err != nil {
+		return fmt.Errorf("failed to marshal info: %w", err)
+	}
+	if err := os.WriteFile(infoPath(c.filePath), data, 0644); err != nil {
+		return fmt.Errorf("failed to write info: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package mem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Summarizer folds messages that fall out of the history window into a running summary.
+type Summarizer interface {
+	// Summarize merges msgs into the previous summary and returns the new summary.
+	Summarize(ctx context.Context, summary string, msgs []*schema.Message) (string, error)
+}
+
+const summaryPrompt = `你是会议助手的记忆模块。请将【已有摘要】与【新的对话】合并为一份新的摘要，供助手在后续对话中回顾上下文。
+要求：
+- 保留用户关心的会议、讨论结论、任务及其负责人和截止日期、人名、日期、数字等关键信息
+- 保留用户的偏好和尚未解决的问题
+- 省略寒暄和重复内容，使用第三人称陈述
+- 不超过 %d 字，只输出摘要本身`
+
+// ModelSummarizer summarizes with a chat model.
+type ModelSummarizer struct {
+	model     model.BaseChatModel
+	maxLength int
+}
+
+// NewModelSummarizer creates a summarizer, maxLength limits the summary length in characters, default 800.
+func NewModelSummarizer(cm model.BaseChatModel, maxLength int) *ModelSummarizer {
+	if maxLength <= 0 {
+		maxLength = 800
+	}
+	return &ModelSummarizer{model: cm, maxLength: maxLength}
+}
+
+func (s *ModelSummarizer) Summarize(ctx context.Context, summary string, msgs []*schema.Message) (string, error) {
+	var b strings.Builder
+	b.WriteString("【已有摘要】\n")
+	if summary == "" {
+		b.WriteString("无\n")
+	} else {
+		b.WriteString(summary)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n【新的对话】\n")
+	for _, msg := range msgs {
+		if msg.Content == "" {
+			continue
+		}
+		b.WriteString(string(msg.Role))
+		b.WriteString(": ")
+		b.WriteString(msg.Content)
+		b.WriteString("\n")
+	}
+
+	out, err := s.model.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(fmt.Sprintf(summaryPrompt, s.maxLength)),
+		schema.UserMessage(b.String()),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to summarize conversation: %w", err)
+	}
+	return strings.TrimSpace(out.Content), nil
+}
+
+// summaryMessage is injected before the history window.
+func summaryMessage(summary string) *schema.Message {
+	return schema.SystemMessage("以下是与用户更早的对话摘要，回答时可以参考：\n" + summary)
+}
+
+// estimateTokens roughly counts one token per CJK character and per 4 ASCII characters.
+func estimateTokens(msg *schema.Message) int {
+	text := msg.Content
+	runes := utf8.RuneCountInString(text)
+	ascii := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] < utf8.RuneSelf {
+			ascii++
+		}
+	}
+	return runes - ascii + ascii/4 + 4
+}
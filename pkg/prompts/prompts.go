@@ -0,0 +1,212 @@
+package prompts
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+
+	"meetingagent/pkg/env"
+)
+
+// 模板名
+const (
+	Agent   = "agent"
+	Summary = "summary"
+)
+
+// DefaultVariant 未指定会议类型或该类型没有单独模板时使用的模板
+const DefaultVariant = "default"
+
+//go:embed templates
+var builtin embed.FS
+
+// ErrNotFound 模板不存在
+var ErrNotFound = errors.New("prompt template not found")
+
+// Template 一个版本的提示词模板，文件为 <name>/<variant>.md，开头的 front matter 中声明 version：
+//
+//	---
+//	version: 2
+//	description: 站会纪要
+//	---
+//	模板正文，变量写作 {meetingDate}，字面量的花括号写作 {{ }}
+type Template struct {
+	Name        string `json:"name"`
+	Variant     string `json:"variant"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	// Hash 正文的摘要，忘记修改 version 时也能区分内容
+	Hash string `json:"hash"`
+	// Source 模板来自 PROMPT_DIR 中的文件还是内置模板
+	Source  string `json:"source"`
+	Content string `json:"-"`
+}
+
+// Ref 记录在输出中的模板版本，例如 summary/standup@v2+1a2b3c4d
+func (t *Template) Ref() string {
+	return fmt.Sprintf("%s/%s@v%s+%s", t.Name, t.Variant, t.Version, t.Hash)
+}
+
+// Render 按 FString 格式替换变量，与 ChatTemplate 节点的规则相同，缺少变量时返回错误
+func (t *Template) Render(ctx context.Context, vars map[string]any) (string, error) {
+	msgs, err := schema.SystemMessage(t.Content).Format(ctx, vars, schema.FString)
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", t.Ref(), err)
+	}
+	return msgs[0].Content, nil
+}
+
+// Store 已加载的模板，PROMPT_DIR 中的文件覆盖同名的内置模板
+type Store struct {
+	dir string
+
+	mu        sync.RWMutex
+	templates map[string]*Template
+}
+
+// NewStore 加载内置模板与 dir 中的模板，dir 不存在时只使用内置模板
+func NewStore(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultStore *Store
+	defaultErr   error
+)
+
+// Default 进程内共享的模板，目录由 PROMPT_DIR 指定，默认 prompts
+func Default() (*Store, error) {
+	defaultOnce.Do(func() {
+		defaultStore, defaultErr = NewStore(env.GetString("PROMPT_DIR", "prompts"))
+	})
+	return defaultStore, defaultErr
+}
+
+// Reload 重新读取全部模板，任一模板有误时保留之前加载的模板
+func (s *Store) Reload() error {
+	sub, _ := fs.Sub(builtin, "templates")
+	templates := make(map[string]*Template)
+	if err := load(templates, sub, "builtin"); err != nil {
+		return err
+	}
+	if _, err := os.Stat(s.dir); err == nil {
+		if err := load(templates, os.DirFS(s.dir), s.dir); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, t := range templates {
+		if old, ok := s.templates[key]; ok && old.Hash != t.Hash {
+			log.Printf("[prompts] %s changed: %s -> %s", key, old.Ref(), t.Ref())
+		}
+	}
+	s.templates = templates
+	return nil
+}
+
+// Get 返回模板 name 的 variant 版本，没有该版本时退回 DefaultVariant
+func (s *Store) Get(name string, variant string) (*Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if variant == "" {
+		variant = DefaultVariant
+	}
+	if t, ok := s.templates[name+"/"+variant]; ok {
+		return t, nil
+	}
+	if t, ok := s.templates[name+"/"+DefaultVariant]; ok {
+		return t, nil
+	}
+	return nil, fmt.Errorf("%w: %s/%s", ErrNotFound, name, variant)
+}
+
+// List 按名称与版本排序返回全部模板
+func (s *Store) List() []*Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*Template, 0, len(s.templates))
+	for _, t := range s.templates {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Variant < list[j].Variant
+	})
+	return list
+}
+
+// load 读取 fsys 中的 <name>/<variant>.md
+func load(templates map[string]*Template, fsys fs.FS, source string) error {
+	files, err := fs.Glob(fsys, "*/*.md")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt %s: %w", file, err)
+		}
+		t, err := parse(string(data))
+		if err != nil {
+			return fmt.Errorf("invalid prompt %s: %w", path.Join(source, file), err)
+		}
+		t.Name = path.Dir(file)
+		t.Variant = strings.TrimSuffix(path.Base(file), ".md")
+		t.Source = source
+		templates[t.Name+"/"+t.Variant] = t
+	}
+	return nil
+}
+
+func parse(data string) (*Template, error) {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	if !strings.HasPrefix(data, "---\n") {
+		return nil, fmt.Errorf("front matter with version is required")
+	}
+	header, body, ok := strings.Cut(data[len("---\n"):], "\n---\n")
+	if !ok {
+		return nil, fmt.Errorf("front matter is not closed")
+	}
+
+	t := &Template{Content: body}
+	for _, line := range strings.Split(header, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "version":
+			t.Version = strings.TrimPrefix(value, "v")
+		case "description":
+			t.Description = value
+		}
+	}
+	if t.Version == "" {
+		return nil, fmt.Errorf("version is required")
+	}
+	sum := sha256.Sum256([]byte(body))
+	t.Hash = hex.EncodeToString(sum[:4])
+	return t, nil
+}
@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TokenBucket 令牌桶，容量为 capacity，每秒补充 rate 个令牌
+type TokenBucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64
+	tokens   float64
+	last     time.Time
+}
+
+// NewTokenBucket 创建令牌桶，初始时桶是满的
+func NewTokenBucket(capacity, ratePerSecond float64) *TokenBucket {
+	return &TokenBucket{
+		capacity: capacity,
+		rate:     ratePerSecond,
+		tokens:   capacity,
+		last:     time.Now(),
+	}
+}
+
+// PerMinute 创建按分钟配额限流的令牌桶，如 RPM、TPM
+func PerMinute(limit int) *TokenBucket {
+	return NewTokenBucket(float64(limit), float64(limit)/60)
+}
+
+// Wait 阻塞直到取得 n 个令牌或 ctx 结束。n 超过桶容量时按容量计算，避免永远等待
+func (b *TokenBucket) Wait(ctx context.Context, n float64) error {
+	if b == nil {
+		return nil
+	}
+	if n > b.capacity {
+		n = b.capacity
+	}
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+
+		if b.tokens >= n {
+			b.tokens -= n
+			b.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((n - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("rate limit wait canceled: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"meetingagent/pkg/env"
+)
+
+type EmbedderConfig struct {
+	// Requests 每分钟请求数配额，nil 表示不限制
+	Requests *TokenBucket
+	// Tokens 每分钟 token 数配额，nil 表示不限制
+	Tokens *TokenBucket
+	// MaxRetries 遇到 429 时的最大重试次数
+	MaxRetries int
+	// BaseBackoff 首次重试前的等待时间，之后指数增长
+	BaseBackoff time.Duration
+	// MaxBackoff 单次重试等待时间上限
+	MaxBackoff time.Duration
+}
+
+// Embedder 对底层 Embedder 做配额限流，并对 429 按指数退避重试
+type Embedder struct {
+	inner  embedding.Embedder
+	config *EmbedderConfig
+}
+
+func NewEmbedder(inner embedding.Embedder, config *EmbedderConfig) (*Embedder, error) {
+	if inner == nil {
+		return nil, fmt.Errorf("embedder cannot be nil")
+	}
+	if config == nil {
+		config = &EmbedderConfig{}
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Minute
+	}
+	return &Embedder{inner: inner, config: config}, nil
+}
+
+var (
+	sharedOnce   sync.Once
+	sharedConfig *EmbedderConfig
+)
+
+// WrapEmbedder 按环境变量配置为 inner 加上限流，同一进程内的所有 Embedder 共享同一份配额：
+//   - EMBEDDING_RPM: 每分钟请求数，0 表示不限制
+//   - EMBEDDING_TPM: 每分钟 token 数，0 表示不限制
+//   - EMBEDDING_MAX_RETRIES: 429 最大重试次数，默认 5
+func WrapEmbedder(inner embedding.Embedder) (embedding.Embedder, error) {
+	sharedOnce.Do(func() {
+		sharedConfig = &EmbedderConfig{
+			MaxRetries: env.GetInt("EMBEDDING_MAX_RETRIES", 5),
+		}
+		if rpm := env.GetInt("EMBEDDING_RPM", 0); rpm > 0 {
+			sharedConfig.Requests = PerMinute(rpm)
+		}
+		if tpm := env.GetInt("EMBEDDING_TPM", 0); tpm > 0 {
+			sharedConfig.Tokens = PerMinute(tpm)
+		}
+	})
+	return NewEmbedder(inner, sharedConfig)
+}
+
+func (e *Embedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	tokens := estimateTokens(texts)
+
+	for attempt := 0; ; attempt++ {
+		if err := e.config.Requests.Wait(ctx, 1); err != nil {
+			return nil, err
+		}
+		if err := e.config.Tokens.Wait(ctx, tokens); err != nil {
+			return nil, err
+		}
+
+		vectors, err := e.inner.EmbedStrings(ctx, texts, opts...)
+		if err == nil || !IsRateLimited(err) || attempt >= e.config.MaxRetries {
+			return vectors, err
+		}
+
+		backoff := e.backoff(attempt)
+		log.Printf("[ratelimit] embedding rate limited, retry %d/%d after %v: %v", attempt+1, e.config.MaxRetries, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff 指数退避并加入最多 50% 的随机抖动，避免并发 worker 同时重试
+func (e *Embedder) backoff(attempt int) time.Duration {
+	d := e.config.BaseBackoff << attempt
+	if d <= 0 || d > e.config.MaxBackoff {
+		d = e.config.MaxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// IsRateLimited 判断错误是否为服务端限流
+func IsRateLimited(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "429") ||
+		strings.Contains(msg, "too many requests") ||
+		strings.Contains(msg, "ratelimit") ||
+		strings.Contains(msg, "rate limit")
+}
+
+// estimateTokens 粗略估计 token 数：中文约一字一 token，其余按 4 字节一 token
+func estimateTokens(texts []string) float64 {
+	var n float64
+	for _, text := range texts {
+		runes := utf8.RuneCountInString(text)
+		ascii := 0
+		for i := 0; i < len(text); i++ {
+			if text[i] < utf8.RuneSelf {
+				ascii++
+			}
+		}
+		n += float64(runes-ascii) + float64(ascii)/4
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	RedisPrefix = "eino:doc:"
+	IndexName   = "vector_index"
+
+	ContentField  = "content"
+	MetadataField = "metadata"
+	VectorField   = "content_vector"
+	DistanceField = "distance"
+
+	// 以下字段单独写入 hash，便于检索时通过 RediSearch 过滤表达式收窄范围
+	MeetingIDField = "meeting_id"
+	SpeakerField   = "speaker"
+	DateField      = "date"
+)
+
+// filterFields 过滤字段及其在索引中的类型
+var filterFields = [][2]string{
+	{MeetingIDField, "TAG"},
+	{SpeakerField, "TAG"},
+	{DateField, "NUMERIC"},
+}
+
+type Config struct {
+	RedisAddr string
+	Dimension int
+}
+
+func InitRedisIndex(ctx context.Context, config *Config) (err error) {
+	if config.Dimension <= 0 {
+		return fmt.Errorf("dimension must be positive")
+	}
+
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.RedisAddr,
+		Protocol: 2,
+	})
+	defer client.Close()
+
+	if err = client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	indexName := fmt.Sprintf("%s%s", RedisPrefix, IndexName)
+
+	// 检查是否存在索引
+	exists, err := client.Do(ctx, "FT.INFO", indexName).Result()
+	if err != nil {
+		if !strings.Contains(err.Error(), "Unknown index name") {
+			return fmt.Errorf("failed to check if index exists: %w", err)
+		}
+		err = nil
+	} else if exists != nil {
+		// 旧版本创建的索引没有过滤字段，这里补上
+		return ensureFilterFields(ctx, client, indexName)
+	}
+
+	// Create new index
+	createIndexArgs := []interface{}{
+		"FT.CREATE", indexName,
+		"ON", "HASH",
+		"PREFIX", "1", RedisPrefix,
+		"SCHEMA",
+		ContentField, "TEXT",
+		MetadataField, "TEXT",
+	}
+	for _, f := range filterFields {
+		createIndexArgs = append(createIndexArgs, f[0], f[1])
+	}
+	createIndexArgs = append(createIndexArgs,
+		VectorField, "VECTOR", "FLAT",
+		"6",
+		"TYPE", "FLOAT32",
+		"DIM", config.Dimension,
+		"DISTANCE_METRIC", "COSINE",
+	)
+
+	if err = client.Do(ctx, createIndexArgs...).Err(); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+
+	// 验证索引是否创建成功
+	if _, err = client.Do(ctx, "FT.INFO", indexName).Result(); err != nil {
+		return fmt.Errorf("failed to verify index creation: %w", err)
+	}
+
+	return nil
+}
+
+func ensureFilterFields(ctx context.Context, client *redis.Client, indexName string) error {
+	for _, f := range filterFields {
+		err := client.Do(ctx, "FT.ALTER", indexName, "SCHEMA", "ADD", f[0], f[1]).Err()
+		if err != nil && !strings.Contains(err.Error(), "Duplicate") {
+			return fmt.Errorf("failed to add field %s to index: %w", f[0], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package reminder
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+const feedSinkName = "feed"
+
+// FeedEvent 站内通知中的一条提醒汇总
+type FeedEvent struct {
+	ID int64 `json:"id"`
+	*Digest
+	Text string `json:"text"`
+}
+
+// Feed 站内通知，保存在内存中最近的 size 条汇总，供 SSE 推送
+type Feed struct {
+	mu     sync.Mutex
+	size   int
+	events []*FeedEvent
+	// seq 从启动时的毫秒时间戳开始递增，重启后客户端带着旧的 Last-Event-ID 重连也不会错过新的事件
+	seq    int64
+	notify chan struct{}
+}
+
+func NewFeed(size int) *Feed {
+	if size <= 0 {
+		size = 100
+	}
+	return &Feed{
+		size:   size,
+		seq:    time.Now().UnixMilli(),
+		notify: make(chan struct{}),
+	}
+}
+
+func (f *Feed) Name() string {
+	return feedSinkName
+}
+
+func (f *Feed) Send(ctx context.Context, digest *Digest) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq++
+	f.events = append(f.events, &FeedEvent{ID: f.seq, Digest: digest, Text: digest.Text()})
+	if len(f.events) > f.size {
+		f.events = f.events[len(f.events)-f.size:]
+	}
+	// 唤醒所有等待中的订阅者
+	close(f.notify)
+	f.notify = make(chan struct{})
+	return nil
+}
+
+// Since 返回 ID 大于 after 且属于 assignee 的事件，assignee 为空时返回全部；
+// 返回的 channel 在有新事件时关闭
+func (f *Feed) Since(after int64, assignee string) ([]*FeedEvent, <-chan struct{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var events []*FeedEvent
+	for _, e := range f.events {
+		if e.ID <= after {
+			continue
+		}
+		if assignee != "" && !strings.EqualFold(e.Assignee, assignee) {
+			continue
+		}
+		events = append(events, e)
+	}
+	return events, f.notify
+}
@@ -0,0 +1,368 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/tool/task"
+)
+
+// Kind 提醒的类型
+type Kind string
+
+const (
+	KindDueSoon Kind = "due_soon"
+	KindOverdue Kind = "overdue"
+)
+
+// Notification 一个任务的提醒
+type Notification struct {
+	Kind            Kind          `json:"kind"`
+	TaskID          string        `json:"task_id"`
+	Title           string        `json:"title"`
+	Assignee        string        `json:"assignee,omitempty"`
+	Priority        task.Priority `json:"priority,omitempty"`
+	Deadline        time.Time     `json:"deadline"`
+	DeadlineText    string        `json:"deadline_text,omitempty"`
+	SourceMeetingID string        `json:"source_meeting_id,omitempty"`
+}
+
+// key 去重用的 key，截止时间改变后会再次提醒
+func (n *Notification) key() string {
+	return n.TaskID + ":" + string(n.Kind) + ":" + n.Deadline.UTC().Format(time.RFC3339)
+}
+
+// Digest 一次发给同一负责人的提醒汇总
+type Digest struct {
+	// Assignee 为空表示未分配负责人的任务
+	Assignee      string          `json:"assignee"`
+	Notifications []*Notification `json:"notifications"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Config 调度器的配置
+type Config struct {
+	Store task.TaskStore
+	// Interval 检查任务的间隔
+	Interval time.Duration
+	// DueSoon 截止时间在此范围内的任务视为即将到期
+	DueSoon time.Duration
+	// Quiet 免打扰时段，期间不向 webhook 与邮件发送，站内通知不受影响，nil 表示不设置
+	Quiet *QuietHours
+	Sinks []Sink
+	// Sent 已发送记录，重启后不会重复发送
+	Sent SentLog
+	// Now 当前时间，默认 time.Now
+	Now func() time.Time
+}
+
+// Scheduler 定期检查即将到期与已逾期的任务，按负责人汇总后发送到各个 Sink
+type Scheduler struct {
+	config Config
+	feed   *Feed
+}
+
+func New(config Config) (*Scheduler, error) {
+	if config.Store == nil {
+		return nil, fmt.Errorf("task store is required")
+	}
+	if config.Sent == nil {
+		return nil, fmt.Errorf("sent log is required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	s := &Scheduler{config: config}
+	for _, sink := range config.Sinks {
+		if feed, ok := sink.(*Feed); ok {
+			s.feed = feed
+		}
+	}
+	if s.feed == nil {
+		s.feed = NewFeed(0)
+	}
+	return s, nil
+}
+
+// NewFromEnv 按环境变量创建：
+//   - REMINDER_INTERVAL: 检查间隔，默认 5m
+//   - REMINDER_DUE_SOON: 截止前多久开始提醒，默认 24h
+//   - REMINDER_SINKS: 逗号分隔的 feed（站内 SSE）、webhook、email，默认 feed
+//   - REMINDER_QUIET_HOURS: 免打扰时段，例如 22:00-08:00，默认不设置
+//   - REMINDER_SENT_STORE: 已发送记录的存储，file 或 redis，默认 TASK_STORE 为 redis 时为 redis，否则为 file
+//
+// webhook 与 email 的配置见 NewWebhookSink 与 NewEmailSinkFromEnv
+func NewFromEnv(store task.TaskStore) (*Scheduler, error) {
+	config := Config{
+		Store:    store,
+		Interval: env.GetDuration("REMINDER_INTERVAL", 5*time.Minute),
+		DueSoon:  env.GetDuration("REMINDER_DUE_SOON", 24*time.Hour),
+	}
+
+	if v := env.GetString("REMINDER_QUIET_HOURS", ""); v != "" {
+		quiet, err := ParseQuietHours(v)
+		if err != nil {
+			return nil, err
+		}
+		config.Quiet = quiet
+	}
+
+	for _, name := range strings.Split(env.GetString("REMINDER_SINKS", "feed"), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "feed":
+			config.Sinks = append(config.Sinks, NewFeed(env.GetInt("REMINDER_FEED_SIZE", 100)))
+		case "webhook":
+			sink, err := NewWebhookSink(env.GetString("REMINDER_WEBHOOK_URL", ""))
+			if err != nil {
+				return nil, err
+			}
+			config.Sinks = append(config.Sinks, sink)
+		case "email":
+			sink, err := NewEmailSinkFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			config.Sinks = append(config.Sinks, sink)
+		default:
+			return nil, fmt.Errorf("unknown reminder sink: %s", name)
+		}
+	}
+
+	sentStore := "file"
+	if env.GetString("TASK_STORE", "jsonl") == "redis" {
+		sentStore = "redis"
+	}
+	sent, err := NewSentLog(env.GetString("REMINDER_SENT_STORE", sentStore))
+	if err != nil {
+		return nil, err
+	}
+	config.Sent = sent
+
+	return New(config)
+}
+
+var (
+	defaultOnce      sync.Once
+	defaultScheduler *Scheduler
+	defaultErr       error
+)
+
+// Default 进程内共享的调度器，使用 task.DefaultStore，配置见 NewFromEnv
+func Default() (*Scheduler, error) {
+	defaultOnce.Do(func() {
+		store, err := task.DefaultStore()
+		if err != nil {
+			defaultErr = fmt.Errorf("failed to init task store: %w", err)
+			return
+		}
+		defaultScheduler, defaultErr = NewFromEnv(store)
+	})
+	return defaultScheduler, defaultErr
+}
+
+// Feed 站内通知，REMINDER_SINKS 中没有 feed 时始终为空
+func (s *Scheduler) Feed() *Feed {
+	return s.feed
+}
+
+// Run 立即检查一次，之后每隔 Interval 检查，直到 ctx 取消
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.config.Sinks) == 0 {
+		log.Printf("[reminder] no sink configured, reminders disabled")
+		return
+	}
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx); err != nil {
+			log.Printf("[reminder] %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce 检查一次任务并发送提醒。发送失败的提醒不会记为已发送，下次检查时重试
+func (s *Scheduler) RunOnce(ctx context.Context) error {
+	now := s.config.Now()
+	notifications, err := s.due(now)
+	if err != nil {
+		return err
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	digests := group(notifications, now)
+	quiet := s.config.Quiet != nil && s.config.Quiet.Contains(now)
+	var errs []error
+	for _, sink := range s.config.Sinks {
+		// 免打扰时段不发送外部通知，留到时段结束后的第一次检查
+		if quiet && sink.Name() != feedSinkName {
+			continue
+		}
+		for _, digest := range digests {
+			if err := s.send(ctx, sink, digest); err != nil {
+				errs = append(errs, fmt.Errorf("failed to send reminder to %s via %s: %w", digest.Assignee, sink.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// due 返回未完成且即将到期或已逾期的任务，无法解析截止时间的任务不提醒
+func (s *Scheduler) due(now time.Time) ([]*Notification, error) {
+	tasks, err := s.config.Store.List(&task.ListParams{
+		Status: []task.Status{task.StatusTodo, task.StatusInProgress, task.StatusBlocked},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	var notifications []*Notification
+	for _, t := range tasks {
+		deadline, err := time.Parse(time.RFC3339, t.Deadline)
+		if err != nil {
+			continue
+		}
+		kind := KindDueSoon
+		switch {
+		case deadline.Before(now):
+			kind = KindOverdue
+		case deadline.Sub(now) > s.config.DueSoon:
+			continue
+		}
+		notifications = append(notifications, &Notification{
+			Kind:            kind,
+			TaskID:          t.ID,
+			Title:           t.Title,
+			Assignee:        t.Assignee,
+			Priority:        t.Priority,
+			Deadline:        deadline,
+			DeadlineText:    t.DeadlineText,
+			SourceMeetingID: t.SourceMeetingID,
+		})
+	}
+	return notifications, nil
+}
+
+// group 按负责人汇总，负责人不区分大小写，同一汇总中已逾期的在前，再按截止时间排序
+func group(notifications []*Notification, now time.Time) []*Digest {
+	var digests []*Digest
+	byAssignee := make(map[string]*Digest)
+	for _, n := range notifications {
+		key := strings.ToLower(n.Assignee)
+		d, ok := byAssignee[key]
+		if !ok {
+			d = &Digest{Assignee: n.Assignee, CreatedAt: now}
+			byAssignee[key] = d
+			digests = append(digests, d)
+		}
+		d.Notifications = append(d.Notifications, n)
+	}
+	for _, d := range digests {
+		sort.SliceStable(d.Notifications, func(i, j int) bool {
+			a, b := d.Notifications[i], d.Notifications[j]
+			if a.Kind != b.Kind {
+				return a.Kind == KindOverdue
+			}
+			return a.Deadline.Before(b.Deadline)
+		})
+	}
+	return digests
+}
+
+// send 只发送该 sink 尚未发送过的提醒，先记录再发送，发送失败时撤销记录，
+// 多个实例共享 redis 记录时同一提醒只会由一个实例发送
+func (s *Scheduler) send(ctx context.Context, sink Sink, digest *Digest) error {
+	pending := &Digest{Assignee: digest.Assignee, CreatedAt: digest.CreatedAt}
+	var keys []string
+	for _, n := range digest.Notifications {
+		key := sink.Name() + ":" + n.key()
+		claimed, err := s.config.Sent.Claim(key)
+		if err != nil {
+			s.release(keys)
+			return err
+		}
+		if claimed {
+			keys = append(keys, key)
+			pending.Notifications = append(pending.Notifications, n)
+		}
+	}
+	if len(pending.Notifications) == 0 {
+		return nil
+	}
+
+	err := sink.Send(ctx, pending)
+	if errors.Is(err, ErrNoRecipient) {
+		s.release(keys)
+		return nil
+	}
+	if err != nil {
+		s.release(keys)
+		return err
+	}
+	return nil
+}
+
+func (s *Scheduler) release(keys []string) {
+	for _, key := range keys {
+		if err := s.config.Sent.Release(key); err != nil {
+			log.Printf("[reminder] failed to release %s: %v", key, err)
+		}
+	}
+}
+
+// QuietHours 每天的免打扰时段，Start 晚于 End 时跨越午夜
+type QuietHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// ParseQuietHours 解析 "22:00-08:00"
+func ParseQuietHours(s string) (*QuietHours, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid quiet hours %q, expected HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid quiet hours %q: %w", s, err)
+	}
+	return &QuietHours{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains t 的当地时间是否在免打扰时段内
+func (q *QuietHours) Contains(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if q.Start <= q.End {
+		return clock >= q.Start && clock < q.End
+	}
+	return clock >= q.Start || clock < q.End
+}
@@ -0,0 +1,71 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	at := func(clock string) time.Time {
+		c, _ := time.Parse("15:04", clock)
+		return time.Date(2025, 5, 7, c.Hour(), c.Minute(), 30, 0, time.Local)
+	}
+
+	tests := []struct {
+		quiet string
+		clock string
+		want  bool
+	}{
+		// 跨越午夜
+		{"22:00-08:00", "22:00", true},
+		{"22:00-08:00", "23:59", true},
+		{"22:00-08:00", "00:00", true},
+		{"22:00-08:00", "07:59", true},
+		{"22:00-08:00", "08:00", false},
+		{"22:00-08:00", "12:00", false},
+		{"22:00-08:00", "21:59", false},
+		// 当天之内
+		{"12:00-13:30", "12:00", true},
+		{"12:00-13:30", "13:29", true},
+		{"12:00-13:30", "13:30", false},
+		{"12:00-13:30", "11:59", false},
+		// 开始与结束相同表示没有免打扰时段
+		{"09:00-09:00", "09:00", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.quiet+" "+tt.clock, func(t *testing.T) {
+			q, err := ParseQuietHours(tt.quiet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := q.Contains(at(tt.clock)); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.clock, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    QuietHours
+		wantErr bool
+	}{
+		{s: "22:00-08:00", want: QuietHours{Start: 22 * time.Hour, End: 8 * time.Hour}},
+		{s: " 12:30 - 13:00 ", want: QuietHours{Start: 12*time.Hour + 30*time.Minute, End: 13 * time.Hour}},
+		{s: "22:00", wantErr: true},
+		{s: "25:00-08:00", wantErr: true},
+		{s: "22:00-8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			q, err := ParseQuietHours(tt.s)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ParseQuietHours(%q) error = %v, wantErr %v", tt.s, err, tt.wantErr)
+			}
+			if err == nil && *q != tt.want {
+				t.Errorf("ParseQuietHours(%q) = %+v, want %+v", tt.s, *q, tt.want)
+			}
+		})
+	}
+}
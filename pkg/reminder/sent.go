@@ -0,0 +1,138 @@
+package reminder
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"meetingagent/pkg/env"
+)
+
+// sentRetention 已发送记录的保留时长，之后同一提醒可能再次发送
+const sentRetention = 90 * 24 * time.Hour
+
+// SentLog 记录已发送的提醒，重启后不会重复发送
+type SentLog interface {
+	// Claim 记录 key 已发送，已经记录过时返回 false
+	Claim(key string) (bool, error)
+	// Release 发送失败时撤销记录
+	Release(key string) error
+}
+
+// NewSentLog 创建 kind 指定的已发送记录：
+//   - file: 保存在 REMINDER_SENT_PATH，默认 TASK_DIR/reminders_sent.json
+//   - redis: 使用 REDIS_ADDR，多个实例共享时同一提醒只发送一次
+func NewSentLog(kind string) (SentLog, error) {
+	switch kind {
+	case "file":
+		path := env.GetString("REMINDER_SENT_PATH",
+			filepath.Join(env.GetString("TASK_DIR", "./data/task"), "reminders_sent.json"))
+		return NewFileSentLog(path)
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr: env.GetString("REDIS_ADDR", "localhost:6379"),
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		return &RedisSentLog{client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown reminder sent store: %s", kind)
+	}
+}
+
+// FileSentLog 将已发送记录保存在一个 json 文件中
+type FileSentLog struct {
+	mu   sync.Mutex
+	path string
+	sent map[string]time.Time
+}
+
+func NewFileSentLog(path string) (*FileSentLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	l := &FileSentLog{path: path, sent: make(map[string]time.Time)}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &l.sent); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+		}
+	}
+	return l, nil
+}
+
+func (l *FileSentLog) Claim(key string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.sent[key]; ok {
+		return false, nil
+	}
+	l.sent[key] = time.Now()
+	if err := l.save(); err != nil {
+		delete(l.sent, key)
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *FileSentLog) Release(key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.sent, key)
+	return l.save()
+}
+
+// save 写入前清理过期的记录，先写临时文件再替换
+func (l *FileSentLog) save() error {
+	for key, at := range l.sent {
+		if time.Since(at) > sentRetention {
+			delete(l.sent, key)
+		}
+	}
+	data, err := json.Marshal(l.sent)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sent log: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sent log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write sent log: %w", err)
+	}
+	return nil
+}
+
+const redisSentPrefix = "eino:reminder:sent:"
+
+// RedisSentLog 每条记录是一个带过期时间的 key
+type RedisSentLog struct {
+	client *redis.Client
+}
+
+func (l *RedisSentLog) Claim(key string) (bool, error) {
+	ok, err := l.client.SetNX(context.Background(), redisSentPrefix+key, time.Now().Format(time.RFC3339), sentRetention).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+	return ok, nil
+}
+
+func (l *RedisSentLog) Release(key string) error {
+	if err := l.client.Del(context.Background(), redisSentPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to release reminder: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"meetingagent/pkg/env"
+)
+
+// Sink 提醒的发送渠道
+type Sink interface {
+	// Name 渠道名称，同时用于区分各渠道的已发送记录
+	Name() string
+	Send(ctx context.Context, digest *Digest) error
+}
+
+// ErrNoRecipient 渠道中找不到该负责人的接收方，这些提醒不记为已发送
+var ErrNoRecipient = errors.New("no recipient for assignee")
+
+// Text 提醒的纯文本内容，用于邮件与站内通知
+func (d *Digest) Text() string {
+	var b strings.Builder
+	name := d.Assignee
+	if name == "" {
+		name = "未分配负责人的任务"
+	}
+	fmt.Fprintf(&b, "%s：有 %d 个任务需要关注\n", name, len(d.Notifications))
+	for _, n := range d.Notifications {
+		label := "即将到期"
+		if n.Kind == KindOverdue {
+			label = "已逾期"
+		}
+		fmt.Fprintf(&b, "- [%s] %s，截止 %s", label, n.Title, n.Deadline.Format("2006-01-02 15:04"))
+		if n.DeadlineText != "" {
+			fmt.Fprintf(&b, "（%s）", n.DeadlineText)
+		}
+		if n.SourceMeetingID != "" {
+			fmt.Fprintf(&b, "，来源会议 %s", n.SourceMeetingID)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// Subject 邮件标题
+func (d *Digest) Subject() string {
+	overdue := 0
+	for _, n := range d.Notifications {
+		if n.Kind == KindOverdue {
+			overdue++
+		}
+	}
+	if overdue > 0 {
+		return fmt.Sprintf("任务提醒：%d 个任务已逾期，%d 个即将到期", overdue, len(d.Notifications)-overdue)
+	}
+	return fmt.Sprintf("任务提醒：%d 个任务即将到期", len(d.Notifications))
+}
+
+// WebhookSink 将汇总以 json POST 到 URL
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink url 来自 REMINDER_WEBHOOK_URL
+func NewWebhookSink(url string) (*WebhookSink, error) {
+	if url == "" {
+		return nil, fmt.Errorf("REMINDER_WEBHOOK_URL is required for webhook reminders")
+	}
+	return &WebhookSink{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+func (s *WebhookSink) Name() string {
+	return "webhook"
+}
+
+// webhookPayload 在汇总之外附带文本，便于直接转发到 IM 机器人
+type webhookPayload struct {
+	*Digest
+	Text string `json:"text"`
+}
+
+func (s *WebhookSink) Send(ctx context.Context, digest *Digest) error {
+	body, err := json.Marshal(&webhookPayload{Digest: digest, Text: digest.Text()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %s: %s", resp.Status, msg)
+	}
+	return nil
+}
+
+// EmailSink 通过 SMTP 发送邮件
+type EmailSink struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	// Recipients 负责人到邮箱的映射，负责人本身是邮箱时直接使用
+	Recipients map[string]string
+	// Fallback 找不到负责人邮箱时的收件人，为空时不发送
+	Fallback string
+}
+
+// NewEmailSinkFromEnv 按环境变量创建：
+//   - SMTP_ADDR: SMTP 服务器 host:port
+//   - SMTP_USERNAME / SMTP_PASSWORD: 为空时不认证
+//   - SMTP_FROM: 发件人，默认 SMTP_USERNAME
+//   - REMINDER_EMAIL_RECIPIENTS: 负责人到邮箱的映射，例如 "张三:zhang@example.com,bob:bob@example.com"
+//   - REMINDER_EMAIL_FALLBACK: 找不到负责人邮箱时的收件人
+func NewEmailSinkFromEnv() (*EmailSink, error) {
+	s := &EmailSink{
+		Addr:       env.GetString("SMTP_ADDR", ""),
+		Username:   env.GetString("SMTP_USERNAME", ""),
+		Password:   env.GetString("SMTP_PASSWORD", ""),
+		Recipients: make(map[string]string),
+		Fallback:   env.GetString("REMINDER_EMAIL_FALLBACK", ""),
+	}
+	s.From = env.GetString("SMTP_FROM", s.Username)
+	if s.Addr == "" || s.From == "" {
+		return nil, fmt.Errorf("SMTP_ADDR and SMTP_FROM are required for email reminders")
+	}
+	for _, item := range strings.Split(env.GetString("REMINDER_EMAIL_RECIPIENTS", ""), ",") {
+		name, addr, ok := strings.Cut(item, ":")
+		if !ok {
+			continue
+		}
+		s.Recipients[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(addr)
+	}
+	return s, nil
+}
+
+func (s *EmailSink) Name() string {
+	return "email"
+}
+
+func (s *EmailSink) recipient(assignee string) string {
+	if addr, ok := s.Recipients[strings.ToLower(assignee)]; ok {
+		return addr
+	}
+	if strings.Contains(assignee, "@") {
+		return assignee
+	}
+	return s.Fallback
+}
+
+func (s *EmailSink) Send(ctx context.Context, digest *Digest) error {
+	to := s.recipient(digest.Assignee)
+	if to == "" {
+		return ErrNoRecipient
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", digest.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", digest.CreatedAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(digest.Text(), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		host, _, _ := strings.Cut(s.Addr, ":")
+		auth = smtp.PlainAuth("", s.Username, s.Password, host)
+	}
+	if err := smtp.SendMail(s.Addr, auth, s.From, []string{to}, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package tasksync
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"meetingagent/pkg/tool/task"
+)
+
+// Connector 外部任务系统
+type Connector interface {
+	// Name 连接器名称，同时用于区分各连接器的关联
+	Name() string
+	// Push 创建或更新外部条目，externalID 为空时创建，返回外部条目的 ID
+	Push(ctx context.Context, t *task.Task, externalID string) (string, error)
+	// Pull 读取外部条目的状态，不支持读取或状态无法对应时返回空字符串
+	Pull(ctx context.Context, externalID string) (task.Status, error)
+}
+
+// RESTConnector 通用的 REST 连接器：
+//   - 创建: POST URL，请求体为任务，响应中的 id 作为外部 ID，没有时使用任务 ID
+//   - 更新: PUT URL/{id}
+//   - 拉取: GET URL/{id}，读取响应中的 status，返回 404 或 405 时视为不支持读取
+//
+// Webhook 为 true 时只向 URL POST {"event": "create" | "update", "external_id": ..., "task": ...}，不拉取状态
+type RESTConnector struct {
+	name    string
+	url     string
+	token   string
+	webhook bool
+	client  *http.Client
+}
+
+// NewRESTConnector token 不为空时以 Bearer 方式认证
+func NewRESTConnector(name, baseURL, token string, webhook bool) (*RESTConnector, error) {
+	if baseURL == "" {
+		return nil, fmt.Errorf("url is required for %s connector", name)
+	}
+	return &RESTConnector{
+		name:    name,
+		url:     strings.TrimRight(baseURL, "/"),
+		token:   token,
+		webhook: webhook,
+		client:  &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+func (c *RESTConnector) Name() string {
+	return c.name
+}
+
+type webhookEvent struct {
+	Event      string     `json:"event"`
+	ExternalID string     `json:"external_id"`
+	Task       *task.Task `json:"task"`
+}
+
+func (c *RESTConnector) Push(ctx context.Context, t *task.Task, externalID string) (string, error) {
+	if c.webhook {
+		event := &webhookEvent{Event: "update", ExternalID: externalID, Task: t}
+		if externalID == "" {
+			event.Event, event.ExternalID = "create", t.ID
+		}
+		if err := c.do(ctx, http.MethodPost, c.url, event, nil); err != nil {
+			return "", err
+		}
+		return event.ExternalID, nil
+	}
+
+	if externalID != "" {
+		return externalID, c.do(ctx, http.MethodPut, c.url+"/"+url.PathEscape(externalID), t, nil)
+	}
+	var created struct {
+		ID json.RawMessage `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, c.url, t, &created); err != nil {
+		return "", err
+	}
+	// id 可能是字符串也可能是数字
+	id := strings.Trim(string(created.ID), `"`)
+	if id == "" || id == "null" {
+		id = t.ID
+	}
+	return id, nil
+}
+
+func (c *RESTConnector) Pull(ctx context.Context, externalID string) (task.Status, error) {
+	if c.webhook {
+		return "", nil
+	}
+	var item struct {
+		Status task.Status `json:"status"`
+	}
+	err := c.do(ctx, http.MethodGet, c.url+"/"+url.PathEscape(externalID), nil, &item)
+	if err, ok := err.(*httpError); ok && (err.status == http.StatusNotFound || err.status == http.StatusMethodNotAllowed) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if !item.Status.Valid() {
+		return "", nil
+	}
+	return item.Status, nil
+}
+
+func (c *RESTConnector) do(ctx context.Context, method, target string, body, out any) error {
+	return doJSON(ctx, c.client, method, target, body, out, func(req *http.Request) {
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+	})
+}
+
+// httpError 外部系统返回的非 2xx 响应
+type httpError struct {
+	method string
+	url    string
+	status int
+	body   string
+}
+
+func (e *httpError) Error() string {
+	return fmt.Sprintf("%s %s returned %d: %s", e.method, e.url, e.status, e.body)
+}
+
+// doJSON 发送 json 请求，out 不为 nil 时解析响应
+func doJSON(ctx context.Context, client *http.Client, method, target string, body, out any, auth func(*http.Request)) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	auth(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s %s: %w", method, target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &httpError{method: method, url: target, status: resp.StatusCode, body: string(msg)}
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response of %s %s: %w", method, target, err)
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package tasksync
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/tool/task"
+)
+
+// JiraConnector 使用 Jira REST API v2 的连接器，任务对应 issue，issue key 作为外部 ID
+type JiraConnector struct {
+	baseURL   string
+	project   string
+	issueType string
+	user      string
+	token     string
+	// statusMap Jira 状态名（小写）到任务状态，未配置的状态按 statusCategory 对应
+	statusMap map[string]task.Status
+	client    *http.Client
+}
+
+// NewJiraConnectorFromEnv 按环境变量创建：
+//   - JIRA_URL: Jira 地址，例如 https://example.atlassian.net
+//   - JIRA_PROJECT: 创建 issue 的项目 key
+//   - JIRA_ISSUE_TYPE: issue 类型，默认 Task
+//   - JIRA_USER、JIRA_TOKEN: 设置 JIRA_USER 时使用 Basic 认证，否则 JIRA_TOKEN 作为 Bearer token
+//   - JIRA_STATUS_MAP: Jira 状态名与任务状态的对应，例如 "Backlog=todo,In Review=in_progress"
+func NewJiraConnectorFromEnv() (*JiraConnector, error) {
+	statusMap, err := parseStatusMap(env.GetString("JIRA_STATUS_MAP", ""))
+	if err != nil {
+		return nil, err
+	}
+	return NewJiraConnector(
+		env.GetString("JIRA_URL", ""),
+		env.GetString("JIRA_PROJECT", ""),
+		env.GetString("JIRA_ISSUE_TYPE", "Task"),
+		env.GetString("JIRA_USER", ""),
+		env.GetString("JIRA_TOKEN", ""),
+		statusMap,
+	)
+}
+
+func NewJiraConnector(baseURL, project, issueType, user, token string, statusMap map[string]task.Status) (*JiraConnector, error) {
+	if baseURL == "" || project == "" {
+		return nil, fmt.Errorf("JIRA_URL and JIRA_PROJECT are required for jira connector")
+	}
+	normalized := make(map[string]task.Status, len(statusMap))
+	for name, status := range statusMap {
+		normalized[strings.ToLower(name)] = status
+	}
+	return &JiraConnector{
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		project:   project,
+		issueType: issueType,
+		user:      user,
+		token:     token,
+		statusMap: normalized,
+		client:    &http.Client{Timeout: 15 * time.Second},
+	}, nil
+}
+
+// parseStatusMap 解析 "Name=status,Name=status"
+func parseStatusMap(s string) (map[string]task.Status, error) {
+	statusMap := make(map[string]task.Status)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		name, status, ok := strings.Cut(pair, "=")
+		if !ok || !task.Status(strings.TrimSpace(status)).Valid() {
+			return nil, fmt.Errorf("invalid JIRA_STATUS_MAP entry %q, expected Name=status", pair)
+		}
+		statusMap[strings.TrimSpace(name)] = task.Status(strings.TrimSpace(status))
+	}
+	return statusMap, nil
+}
+
+func (c *JiraConnector) Name() string {
+	return "jira"
+}
+
+type jiraName struct {
+	Name string `json:"name"`
+}
+
+type jiraKey struct {
+	Key string `json:"key"`
+}
+
+type jiraStatus struct {
+	Name           string  `json:"name"`
+	StatusCategory jiraKey `json:"statusCategory"`
+}
+
+type jiraTransition struct {
+	ID string     `json:"id"`
+	To jiraStatus `json:"to"`
+}
+
+func (c *JiraConnector) Push(ctx context.Context, t *task.Task, externalID string) (string, error) {
+	fields := c.fields(t)
+	if externalID == "" {
+		fields["project"] = jiraKey{Key: c.project}
+		fields["issuetype"] = jiraName{Name: c.issueType}
+		var created jiraKey
+		if err := c.do(ctx, http.MethodPost, "/rest/api/2/issue", map[string]any{"fields": fields}, &created); err != nil {
+			return "", err
+		}
+		if created.Key == "" {
+			return "", fmt.Errorf("jira returned no issue key")
+		}
+		externalID = created.Key
+	} else if err := c.do(ctx, http.MethodPut, c.issuePath(externalID), map[string]any{"fields": fields}, nil); err != nil {
+		return "", err
+	}
+
+	// Jira 的状态只能通过 transition 修改
+	if err := c.transition(ctx, externalID, t.Status); err != nil {
+		return externalID, err
+	}
+	return externalID, nil
+}
+
+// fields 任务对应的 issue 字段，没有的字段传空值以便更新时清除
+func (c *JiraConnector) fields(t *task.Task) map[string]any {
+	description := t.Content
+	if t.SourceMeetingID != "" {
+		description += fmt.Sprintf("\n\nSource meeting: %s %s", t.SourceMeetingID, t.SourceTimestamp)
+	}
+	fields := map[string]any{
+		"summary":     t.Title,
+		"description": strings.TrimSpace(description),
+		"labels":      jiraLabels(t.Tags),
+		"priority":    jiraName{Name: jiraPriority(t.Priority)},
+		"duedate":     nil,
+	}
+	if deadline, err := time.Parse(time.RFC3339, t.Deadline); err == nil {
+		fields["duedate"] = deadline.Format(time.DateOnly)
+	}
+	return fields
+}
+
+// jiraLabels Jira 的 label 不能包含空格
+func jiraLabels(tags []string) []string {
+	labels := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.Join(strings.Fields(tag), "-"); tag != "" {
+			labels = append(labels, tag)
+		}
+	}
+	return labels
+}
+
+func jiraPriority(p task.Priority) string {
+	switch p {
+	case task.PriorityUrgent:
+		return "Highest"
+	case task.PriorityHigh:
+		return "High"
+	case task.PriorityLow:
+		return "Low"
+	default:
+		return "Medium"
+	}
+}
+
+// transition 将 issue 转到与 status 对应的状态，已经对应或没有可用的 transition 时不处理
+func (c *JiraConnector) transition(ctx context.Context, key string, status task.Status) error {
+	var issue struct {
+		Fields struct {
+			Status jiraStatus `json:"status"`
+		} `json:"fields"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.issuePath(key)+"?fields=status", nil, &issue); err != nil {
+		return err
+	}
+	if c.toStatus(issue.Fields.Status) == status {
+		return nil
+	}
+
+	var available struct {
+		Transitions []jiraTransition `json:"transitions"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.issuePath(key)+"/transitions", nil, &available); err != nil {
+		return err
+	}
+	// 优先选择映射后完全一致的状态，其次选择分类一致的状态
+	var chosen *jiraTransition
+	for i, tr := range available.Transitions {
+		if mapped, ok := c.statusMap[strings.ToLower(tr.To.Name)]; ok && mapped == status {
+			chosen = &available.Transitions[i]
+			break
+		}
+		if chosen == nil && tr.To.StatusCategory.Key == jiraCategory(status) {
+			chosen = &available.Transitions[i]
+		}
+	}
+	if chosen == nil {
+		return nil
+	}
+	body := map[string]any{"transition": map[string]string{"id": chosen.ID}}
+	return c.do(ctx, http.MethodPost, c.issuePath(key)+"/transitions", body, nil)
+}
+
+func (c *JiraConnector) Pull(ctx context.Context, externalID string) (task.Status, error) {
+	var issue struct {
+		Fields struct {
+			Status jiraStatus `json:"status"`
+		} `json:"fields"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.issuePath(externalID)+"?fields=status", nil, &issue); err != nil {
+		return "", err
+	}
+	return c.toStatus(issue.Fields.Status), nil
+}
+
+// toStatus 先按 JIRA_STATUS_MAP 对应，再按 statusCategory 对应
+func (c *JiraConnector) toStatus(s jiraStatus) task.Status {
+	if status, ok := c.statusMap[strings.ToLower(s.Name)]; ok {
+		return status
+	}
+	switch s.StatusCategory.Key {
+	case "new":
+		return task.StatusTodo
+	case "indeterminate":
+		return task.StatusInProgress
+	case "done":
+		return task.StatusDone
+	}
+	return ""
+}
+
+// jiraCategory 任务状态对应的 statusCategory
+func jiraCategory(status task.Status) string {
+	switch status {
+	case task.StatusInProgress, task.StatusBlocked:
+		return "indeterminate"
+	case task.StatusDone, task.StatusCancelled:
+		return "done"
+	default:
+		return "new"
+	}
+}
+
+func (c *JiraConnector) issuePath(key string) string {
+	return "/rest/api/2/issue/" + url.PathEscape(key)
+}
+
+func (c *JiraConnector) do(ctx context.Context, method, path string, body, out any) error {
+	return doJSON(ctx, c.client, method, c.baseURL+path, body, out, func(req *http.Request) {
+		switch {
+		case c.user != "":
+			req.SetBasicAuth(c.user, c.token)
+		case c.token != "":
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package tasksync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/tool/task"
+)
+
+// Link 任务与外部系统中对应条目的关联
+type Link struct {
+	Connector  string `json:"connector"`
+	TaskID     string `json:"task_id"`
+	ExternalID string `json:"external_id"`
+	// ETag 最近一次推送或拉取后任务的 ETag，任务的 ETag 与它不同时需要再次推送
+	ETag string `json:"etag"`
+	// RemoteStatus 最近一次读取到的外部状态，外部状态与它不同时才更新任务
+	RemoteStatus task.Status `json:"remote_status,omitempty"`
+	SyncedAt     time.Time   `json:"synced_at"`
+	// Error 最近一次同步失败的原因，成功后清空
+	Error string `json:"error,omitempty"`
+}
+
+// LinkStore 保存任务与外部条目的关联
+type LinkStore interface {
+	// Get 返回任务在 connector 中的关联，没有时返回 nil
+	Get(connector, taskID string) (*Link, error)
+	Put(link *Link) error
+}
+
+// NewLinkStore 创建 kind 指定的关联存储：
+//   - file: 保存在 SYNC_LINK_PATH，默认 TASK_DIR/sync_links.json
+//   - redis: 使用 REDIS_ADDR，多个实例共享
+func NewLinkStore(kind string) (LinkStore, error) {
+	switch kind {
+	case "file":
+		path := env.GetString("SYNC_LINK_PATH",
+			filepath.Join(env.GetString("TASK_DIR", "./data/task"), "sync_links.json"))
+		return NewFileLinkStore(path)
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr: env.GetString("REDIS_ADDR", "localhost:6379"),
+		})
+		if err := client.Ping(context.Background()).Err(); err != nil {
+			return nil, fmt.Errorf("failed to connect to redis: %w", err)
+		}
+		return &RedisLinkStore{client: client}, nil
+	default:
+		return nil, fmt.Errorf("unknown sync link store: %s", kind)
+	}
+}
+
+func linkKey(connector, taskID string) string {
+	return connector + ":" + taskID
+}
+
+// FileLinkStore 将全部关联保存在一个 json 文件中
+type FileLinkStore struct {
+	mu    sync.Mutex
+	path  string
+	links map[string]*Link
+}
+
+func NewFileLinkStore(path string) (*FileLinkStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	s := &FileLinkStore{path: path, links: make(map[string]*Link)}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &s.links); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+		}
+	}
+	return s, nil
+}
+
+func (s *FileLinkStore) Get(connector, taskID string) (*Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[linkKey(connector, taskID)]
+	if !ok {
+		return nil, nil
+	}
+	copied := *link
+	return &copied, nil
+}
+
+// Put 先写临时文件再替换
+func (s *FileLinkStore) Put(link *Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	copied := *link
+	s.links[linkKey(link.Connector, link.TaskID)] = &copied
+	data, err := json.Marshal(s.links)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync links: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write sync links: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write sync links: %w", err)
+	}
+	return nil
+}
+
+const redisLinkPrefix = "eino:sync:link:"
+
+// RedisLinkStore 每个关联是一个 json 字符串
+type RedisLinkStore struct {
+	client *redis.Client
+}
+
+func (s *RedisLinkStore) Get(connector, taskID string) (*Link, error) {
+	data, err := s.client.Get(context.Background(), redisLinkPrefix+linkKey(connector, taskID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync link: %w", err)
+	}
+	var link Link
+	if err := json.Unmarshal([]byte(data), &link); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sync link: %w", err)
+	}
+	return &link, nil
+}
+
+func (s *RedisLinkStore) Put(link *Link) error {
+	data, err := json.Marshal(link)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sync link: %w", err)
+	}
+	if err := s.client.Set(context.Background(), redisLinkPrefix+linkKey(link.Connector, link.TaskID), data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to write sync link: %w", err)
+	}
+	return nil
+}
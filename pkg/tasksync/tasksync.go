@@ -0,0 +1,270 @@
+package tasksync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/tool/task"
+)
+
+// Config 同步器的配置
+type Config struct {
+	Service    *task.Service
+	Connectors []Connector
+	Links      LinkStore
+	// Interval 同步的间隔
+	Interval time.Duration
+}
+
+// Syncer 定期将任务的创建与修改推送到外部任务系统，并拉取外部的状态变化
+type Syncer struct {
+	config Config
+	// mu 避免 Run 与手动触发的 RunOnce 同时推送同一任务
+	mu sync.Mutex
+}
+
+func New(config Config) (*Syncer, error) {
+	if config.Service == nil {
+		return nil, fmt.Errorf("task service is required")
+	}
+	if config.Links == nil {
+		return nil, fmt.Errorf("link store is required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = 2 * time.Minute
+	}
+	return &Syncer{config: config}, nil
+}
+
+// NewFromEnv 按环境变量创建：
+//   - SYNC_CONNECTORS: 逗号分隔的 rest、webhook、jira，默认为空即不同步
+//   - SYNC_INTERVAL: 同步间隔，默认 2m
+//   - SYNC_REST_URL、SYNC_WEBHOOK_URL: rest 与 webhook 连接器的地址，SYNC_REST_TOKEN 作为 Bearer token
+//   - SYNC_LINK_STORE: 外部 ID 的存储，file 或 redis，默认 TASK_STORE 为 redis 时为 redis，否则为 file
+//
+// jira 连接器的配置见 NewJiraConnectorFromEnv
+func NewFromEnv(service *task.Service) (*Syncer, error) {
+	config := Config{
+		Service:  service,
+		Interval: env.GetDuration("SYNC_INTERVAL", 2*time.Minute),
+	}
+
+	token := env.GetString("SYNC_REST_TOKEN", "")
+	for _, name := range strings.Split(env.GetString("SYNC_CONNECTORS", ""), ",") {
+		switch name = strings.TrimSpace(name); name {
+		case "":
+		case "rest":
+			connector, err := NewRESTConnector(name, env.GetString("SYNC_REST_URL", ""), token, false)
+			if err != nil {
+				return nil, err
+			}
+			config.Connectors = append(config.Connectors, connector)
+		case "webhook":
+			connector, err := NewRESTConnector(name, env.GetString("SYNC_WEBHOOK_URL", ""), token, true)
+			if err != nil {
+				return nil, err
+			}
+			config.Connectors = append(config.Connectors, connector)
+		case "jira":
+			connector, err := NewJiraConnectorFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			config.Connectors = append(config.Connectors, connector)
+		default:
+			return nil, fmt.Errorf("unknown sync connector: %s", name)
+		}
+	}
+
+	linkStore := "file"
+	if env.GetString("TASK_STORE", "jsonl") == "redis" {
+		linkStore = "redis"
+	}
+	links, err := NewLinkStore(env.GetString("SYNC_LINK_STORE", linkStore))
+	if err != nil {
+		return nil, err
+	}
+	config.Links = links
+
+	return New(config)
+}
+
+var (
+	defaultOnce   sync.Once
+	defaultSyncer *Syncer
+	defaultErr    error
+)
+
+// Default 进程内共享的同步器，使用 task.DefaultService，配置见 NewFromEnv
+func Default() (*Syncer, error) {
+	defaultOnce.Do(func() {
+		service, err := task.DefaultService()
+		if err != nil {
+			defaultErr = fmt.Errorf("failed to init task service: %w", err)
+			return
+		}
+		defaultSyncer, defaultErr = NewFromEnv(service)
+	})
+	return defaultSyncer, defaultErr
+}
+
+// Run 立即同步一次，之后每隔 Interval 同步，直到 ctx 取消
+func (s *Syncer) Run(ctx context.Context) {
+	if len(s.config.Connectors) == 0 {
+		log.Printf("[tasksync] no connector configured, sync disabled")
+		return
+	}
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx); err != nil {
+			log.Printf("[tasksync] %v", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// RunOnce 对每个连接器同步一次全部任务。单个任务失败不影响其他任务，原因记录在关联中，下次同步时重试
+func (s *Syncer) RunOnce(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.config.Service.List(nil)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, t := range tasks {
+		// 从一个连接器拉取的状态变化会推送到其他连接器
+		for _, connector := range s.config.Connectors {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if t, err = s.sync(ctx, connector, t); err != nil {
+				errs = append(errs, fmt.Errorf("failed to sync task %s to %s: %w", t.ID, connector.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Links 任务在各个连接器中的关联，没有同步过的连接器不返回
+func (s *Syncer) Links(taskID string) ([]*Link, error) {
+	var links []*Link
+	for _, connector := range s.config.Connectors {
+		link, err := s.config.Links.Get(connector.Name(), taskID)
+		if err != nil {
+			return nil, err
+		}
+		if link != nil {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+// sync 先拉取外部状态，外部状态发生变化时更新任务，再在任务有修改时推送，返回最新的任务
+func (s *Syncer) sync(ctx context.Context, connector Connector, t *task.Task) (*task.Task, error) {
+	link, err := s.config.Links.Get(connector.Name(), t.ID)
+	if err != nil {
+		return t, err
+	}
+
+	if link != nil {
+		remoteStatus := link.RemoteStatus
+		pulled, err := s.pull(ctx, connector, link, t)
+		if err != nil {
+			return t, s.fail(link, err)
+		}
+		if pulled == nil {
+			return t, nil
+		}
+		t = pulled
+		if link.ETag == task.ETag(t) && link.Error == "" {
+			if link.RemoteStatus == remoteStatus {
+				return t, nil
+			}
+			return t, s.config.Links.Put(link)
+		}
+	}
+
+	etag := task.ETag(t)
+	if link == nil {
+		link = &Link{Connector: connector.Name(), TaskID: t.ID}
+	}
+	externalID, err := connector.Push(ctx, t, link.ExternalID)
+	if externalID != "" {
+		// 创建成功但后续步骤失败时也要保存外部 ID，避免重复创建
+		link.ExternalID = externalID
+	}
+	if err != nil {
+		return t, s.fail(link, err)
+	}
+
+	// 状态对应可能有损失（例如 cancelled 推送后在 Jira 中为完成），
+	// 记录推送后的外部状态，下次拉取时不会把它当作外部的修改
+	status, err := connector.Pull(ctx, link.ExternalID)
+	if err != nil {
+		return t, s.fail(link, err)
+	}
+	link.RemoteStatus = status
+	link.ETag = etag
+	link.SyncedAt = time.Now()
+	link.Error = ""
+	return t, s.config.Links.Put(link)
+}
+
+// pull 外部状态与上次记录的不同时以外部为准更新任务，返回最新的任务；
+// 任务在此期间被修改时返回 nil，本次跳过，下次同步时重新比较
+func (s *Syncer) pull(ctx context.Context, connector Connector, link *Link, t *task.Task) (*task.Task, error) {
+	status, err := connector.Pull(ctx, link.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	if status == "" || status == link.RemoteStatus {
+		return t, nil
+	}
+	if status == t.Status {
+		link.RemoteStatus = status
+		return t, nil
+	}
+
+	etag := task.ETag(t)
+	ctx = task.WithActor(ctx, task.Actor{Kind: task.ActorSync, ID: connector.Name()})
+	updated, err := s.config.Service.Patch(ctx, t.ID, &task.TaskPatch{Status: &status}, etag)
+	if errors.Is(err, task.ErrPreconditionFailed) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	link.RemoteStatus = status
+	// 任务在此之前已经推送过时，这次修改来自外部，不需要再推送回去
+	if link.ETag == etag {
+		link.ETag = task.ETag(updated)
+	}
+	return updated, nil
+}
+
+// fail 记录失败原因，保留已有的外部 ID
+func (s *Syncer) fail(link *Link, err error) error {
+	link.Error = err.Error()
+	link.SyncedAt = time.Now()
+	if link.ExternalID == "" {
+		return err
+	}
+	if putErr := s.config.Links.Put(link); putErr != nil {
+		return errors.Join(err, putErr)
+	}
+	return err
+}
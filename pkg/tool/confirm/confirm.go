@@ -0,0 +1,192 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package confirm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	"github.com/google/uuid"
+)
+
+// DefaultPolicy 默认需要确认的工具与 action
+const DefaultPolicy = "task_manager:delete,update"
+
+// Policy 需要用户确认的工具调用，key 为工具名，value 为 action 集合，"*" 表示该工具的所有调用
+type Policy map[string]map[string]struct{}
+
+// ParsePolicy 解析 "tool:action1,action2;tool2:*" 格式的策略，空字符串表示不需要确认
+func ParsePolicy(s string) (Policy, error) {
+	p := make(Policy)
+	for _, item := range strings.Split(s, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, actions, ok := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid confirmation policy: %s", item)
+		}
+		set := make(map[string]struct{})
+		for _, action := range strings.Split(actions, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				set[action] = struct{}{}
+			}
+		}
+		if len(set) == 0 {
+			return nil, fmt.Errorf("invalid confirmation policy, no action for tool %s", name)
+		}
+		p[name] = set
+	}
+	return p, nil
+}
+
+// PolicyFromEnv 读取 TOOL_CONFIRMATION，未设置时使用 DefaultPolicy，设置为空表示不需要确认
+func PolicyFromEnv() (Policy, error) {
+	if v, ok := os.LookupEnv("TOOL_CONFIRMATION"); ok {
+		return ParsePolicy(v)
+	}
+	return ParsePolicy(DefaultPolicy)
+}
+
+// Requires 工具调用是否需要确认，action 为参数中的 action 字段，没有时为空
+func (p Policy) Requires(toolName string, action string) bool {
+	actions, ok := p[toolName]
+	if !ok {
+		return false
+	}
+	if _, ok := actions["*"]; ok {
+		return true
+	}
+	_, ok = actions[action]
+	return ok
+}
+
+// Request 等待用户确认的工具调用
+type Request struct {
+	ID         string          `json:"id"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+	Tool       string          `json:"tool"`
+	Action     string          `json:"action,omitempty"`
+	Arguments  json.RawMessage `json:"arguments"`
+}
+
+// Decision 用户的确认结果
+type Decision struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// Confirmer 向用户请求确认并阻塞到用户做出决定，ctx 取消时返回 ctx.Err()
+type Confirmer func(ctx context.Context, req *Request) (*Decision, error)
+
+type confirmerKey struct{}
+
+// WithConfirmer 为一次运行指定确认方式，没有 Confirmer 的运行中需要确认的工具调用一律拒绝
+func WithConfirmer(ctx context.Context, confirmer Confirmer) context.Context {
+	return context.WithValue(ctx, confirmerKey{}, confirmer)
+}
+
+func confirmerFrom(ctx context.Context) Confirmer {
+	c, _ := ctx.Value(confirmerKey{}).(Confirmer)
+	return c
+}
+
+// Wrap 为 policy 中的工具加上确认，其余工具原样返回
+func Wrap(ctx context.Context, tools []tool.BaseTool, policy Policy) ([]tool.BaseTool, error) {
+	wrapped := make([]tool.BaseTool, 0, len(tools))
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := policy[info.Name]; !ok {
+			wrapped = append(wrapped, t)
+			continue
+		}
+		it, ok := t.(tool.InvokableTool)
+		if !ok {
+			log.Printf("[confirm] WARN: tool %s is not invokable, confirmation is not supported", info.Name)
+			wrapped = append(wrapped, t)
+			continue
+		}
+		wrapped = append(wrapped, &confirmTool{inner: it, name: info.Name, policy: policy})
+	}
+	return wrapped, nil
+}
+
+type confirmTool struct {
+	inner  tool.InvokableTool
+	name   string
+	policy Policy
+}
+
+func (t *confirmTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return t.inner.Info(ctx)
+}
+
+func (t *confirmTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	var args struct {
+		Action string `json:"action"`
+	}
+	_ = json.Unmarshal([]byte(argumentsInJSON), &args)
+	if !t.policy.Requires(t.name, args.Action) {
+		return t.inner.InvokableRun(ctx, argumentsInJSON, opts...)
+	}
+
+	decision := &Decision{Reason: "no confirmation channel available"}
+	if confirmer := confirmerFrom(ctx); confirmer != nil {
+		arguments := json.RawMessage(argumentsInJSON)
+		if !json.Valid(arguments) {
+			arguments, _ = json.Marshal(argumentsInJSON)
+		}
+		var err error
+		decision, err = confirmer(ctx, &Request{
+			ID:         uuid.NewString(),
+			ToolCallID: compose.GetToolCallID(ctx),
+			Tool:       t.name,
+			Action:     args.Action,
+			Arguments:  arguments,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if !decision.Approved {
+		return rejected(decision.Reason), nil
+	}
+	return t.inner.InvokableRun(ctx, argumentsInJSON, opts...)
+}
+
+// rejected 拒绝时返回给模型的结果，模型据此告知用户操作未执行
+func rejected(reason string) string {
+	message := "the user rejected this operation, it was not performed"
+	if reason != "" {
+		message += ": " + reason
+	}
+	b, _ := json.Marshal(map[string]string{"status": "rejected", "message": message})
+	return string(b)
+}
@@ -0,0 +1,74 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package confirm
+
+import "testing"
+
+func TestParsePolicy(t *testing.T) {
+	type call struct {
+		tool, action string
+		want         bool
+	}
+	tests := []struct {
+		name    string
+		policy  string
+		wantErr bool
+		calls   []call
+	}{
+		{
+			name:   "default",
+			policy: DefaultPolicy,
+			calls: []call{
+				{"task_manager", "delete", true},
+				{"task_manager", "update", true},
+				{"task_manager", "add", false},
+				{"meeting_search", "", false},
+			},
+		},
+		{
+			name:   "wildcard and spaces",
+			policy: " task_manager : delete , update ; sync:* ;",
+			calls: []call{
+				{"task_manager", "update", true},
+				{"task_manager", "list", false},
+				{"sync", "", true},
+				{"sync", "push", true},
+			},
+		},
+		{
+			name:   "empty policy requires nothing",
+			policy: "",
+			calls:  []call{{"task_manager", "delete", false}},
+		},
+		{name: "missing colon", policy: "task_manager", wantErr: true},
+		{name: "missing tool", policy: ":delete", wantErr: true},
+		{name: "missing action", policy: "task_manager: , ", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := ParsePolicy(tt.policy)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("ParsePolicy(%q) error = %v, wantErr %v", tt.policy, err, tt.wantErr)
+			}
+			for _, c := range tt.calls {
+				if got := p.Requires(c.tool, c.action); got != c.want {
+					t.Errorf("Requires(%q, %q) = %v, want %v", c.tool, c.action, got, c.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package deadline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+
+	resolver "meetingagent/pkg/deadline"
+	"meetingagent/pkg/tool/meeting"
+)
+
+// MeetingDateFunc 返回会议的日期，作为相对表达的锚点
+type MeetingDateFunc func(ctx context.Context, meetingID string) (time.Time, error)
+
+// MeetingDateFromRepository 以会议的创建时间作为会议日期
+func MeetingDateFromRepository(repo *meeting.Repository) MeetingDateFunc {
+	return func(ctx context.Context, meetingID string) (time.Time, error) {
+		m, err := repo.Get(ctx, meetingID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if m.CreatedAt.IsZero() {
+			return time.Time{}, fmt.Errorf("meeting %s has no date", meetingID)
+		}
+		return m.CreatedAt, nil
+	}
+}
+
+// Anchor 有会议ID 时取会议日期，否则或读取失败时取当前时间
+func Anchor(ctx context.Context, meetingDate MeetingDateFunc, meetingID string) time.Time {
+	if meetingID == "" || meetingDate == nil {
+		return time.Now()
+	}
+	date, err := meetingDate(ctx, meetingID)
+	if err != nil {
+		log.Printf("[deadline] failed to get date of meeting %s, resolving relative to now: %v", meetingID, err)
+		return time.Now()
+	}
+	return date
+}
+
+type ToolConfig struct {
+	// MeetingDate 默认读取 POST /meeting 保存的会议
+	MeetingDate MeetingDateFunc
+}
+
+type ResolveDeadlineRequest struct {
+	Expression    string `json:"expression" jsonschema:"description=deadline as said in the meeting, e.g. 下周五, 月底前, end of sprint, friday 3pm,required"`
+	MeetingID     string `json:"meeting_id,omitempty" jsonschema:"description=id of the meeting the expression comes from, relative dates are counted from the meeting date"`
+	ReferenceDate string `json:"reference_date,omitempty" jsonschema:"description=date the expression is relative to, YYYY-MM-DD, overrides the meeting date, default today"`
+}
+
+type ResolveDeadlineResponse struct {
+	Expression string `json:"expression"`
+	// Deadline RFC3339，只有日期时为当天 23:59:59
+	Deadline string `json:"deadline,omitempty"`
+	Weekday  string `json:"weekday,omitempty"`
+	DateOnly bool   `json:"date_only,omitempty"`
+	// Anchor 换算所依据的日期
+	Anchor string `json:"anchor"`
+	Error  string `json:"error,omitempty"`
+}
+
+type toolImpl struct {
+	meetingDate MeetingDateFunc
+}
+
+// NewTool 创建 resolve_deadline 工具，将会议中说的截止时间换算为 RFC3339
+func NewTool(ctx context.Context, config *ToolConfig) (tool.BaseTool, error) {
+	if config == nil {
+		config = &ToolConfig{}
+	}
+	if config.MeetingDate == nil {
+		config.MeetingDate = MeetingDateFromRepository(meeting.NewRepository(nil))
+	}
+	t := &toolImpl{meetingDate: config.MeetingDate}
+	return utils.InferTool("resolve_deadline",
+		"convert a relative or natural language deadline (Chinese or English, e.g. 下周五, 月底, in 3 days, end of sprint) into an exact RFC3339 time, counted from the meeting date rather than today",
+		t.Resolve)
+}
+
+func (t *toolImpl) Resolve(ctx context.Context, req *ResolveDeadlineRequest) (*ResolveDeadlineResponse, error) {
+	res := &ResolveDeadlineResponse{Expression: req.Expression}
+
+	var anchor time.Time
+	if req.ReferenceDate != "" {
+		date, err := time.ParseInLocation(time.DateOnly, req.ReferenceDate, time.Local)
+		if err != nil {
+			res.Error = fmt.Sprintf("invalid reference_date %q, expected YYYY-MM-DD", req.ReferenceDate)
+			return res, nil
+		}
+		anchor = date
+	} else {
+		anchor = Anchor(ctx, t.meetingDate, req.MeetingID)
+	}
+	res.Anchor = anchor.Format(time.DateOnly)
+
+	resolved, err := resolver.Resolve(req.Expression, anchor)
+	if errors.Is(err, resolver.ErrUnresolved) {
+		res.Error = "cannot resolve the expression, ask the user for an exact date"
+		return res, nil
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.Deadline = resolved.RFC3339()
+	res.Weekday = resolved.Time.Weekday().String()
+	res.DateOnly = resolved.DateOnly
+	return res, nil
+}
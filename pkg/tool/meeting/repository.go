@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package meeting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"meetingagent/models"
+	"meetingagent/pkg/env"
+	redisstore "meetingagent/redis"
+)
+
+// keyPrefix 会议数据在 Redis 中的 key 前缀，与 handlers.CreateMeeting 保持一致
+const keyPrefix = "meeting:"
+
+// Utterance 会议记录中的一段发言
+type Utterance struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Speaker string `json:"speaker"`
+	Text    string `json:"text"`
+}
+
+// Meeting 从 models.Meeting 解析出的会议，包含逐条发言
+type Meeting struct {
+	ID         string      `json:"id"`
+	CreatedAt  time.Time   `json:"created_at"`
+	Summary    string      `json:"summary,omitempty"`
+	Utterances []Utterance `json:"-"`
+}
+
+// Title 摘要的第一个标题，没有摘要时为空
+func (m *Meeting) Title() string {
+	for _, line := range strings.Split(m.Summary, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.TrimLeft(line, "#"))
+		}
+	}
+	return ""
+}
+
+// Speakers 按首次发言顺序返回发言人
+func (m *Meeting) Speakers() []string {
+	seen := make(map[string]struct{})
+	speakers := make([]string, 0)
+	for _, u := range m.Utterances {
+		if _, ok := seen[u.Speaker]; ok {
+			continue
+		}
+		seen[u.Speaker] = struct{}{}
+		speakers = append(speakers, u.Speaker)
+	}
+	return speakers
+}
+
+// Duration 会议时长，取最后一段发言的结束时间
+func (m *Meeting) Duration() time.Duration {
+	var d time.Duration
+	for _, u := range m.Utterances {
+		if to, err := ParseOffset(u.To); err == nil && to > d {
+			d = to
+		}
+	}
+	return d
+}
+
+// Repository 读取 POST /meeting 保存在 Redis 中的会议
+type Repository struct {
+	client *redis.Client
+}
+
+// NewRepository client 为 nil 时使用服务端已初始化的 redis.Client，否则按 REDIS_ADDR 新建连接
+func NewRepository(client *redis.Client) *Repository {
+	if client == nil {
+		client = redisstore.Client
+	}
+	if client == nil {
+		client = redis.NewClient(&redis.Options{
+			Addr: env.GetString("REDIS_ADDR", "localhost:6379"),
+		})
+	}
+	return &Repository{client: client}
+}
+
+// ErrNotFound 会议不存在
+var ErrNotFound = errors.New("meeting not found")
+
+func (r *Repository) Get(ctx context.Context, id string) (*Meeting, error) {
+	data, err := r.client.Get(ctx, keyPrefix+id).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get meeting %s: %w", id, err)
+	}
+	return parseMeeting([]byte(data))
+}
+
+// List 返回全部会议，按创建时间倒序
+func (r *Repository) List(ctx context.Context) ([]*Meeting, error) {
+	var meetings []*Meeting
+	iter := r.client.Scan(ctx, 0, keyPrefix+"*", 200).Iterator()
+	for iter.Next(ctx) {
+		data, err := r.client.Get(ctx, iter.Val()).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get meeting %s: %w", iter.Val(), err)
+		}
+		m, err := parseMeeting([]byte(data))
+		if err != nil {
+			continue
+		}
+		meetings = append(meetings, m)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list meetings: %w", err)
+	}
+
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].CreatedAt.After(meetings[j].CreatedAt)
+	})
+	return meetings, nil
+}
+
+// transcript POST /meeting 请求体中的会议记录格式
+type transcript struct {
+	Contents []struct {
+		TimeFrom string `json:"time_from"`
+		TimeTo   string `json:"time_to"`
+		User     string `json:"user"`
+		Content  struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	} `json:"contents"`
+}
+
+func parseMeeting(data []byte) (*Meeting, error) {
+	var raw models.Meeting
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal meeting: %w", err)
+	}
+	m := &Meeting{
+		ID:      raw.ID,
+		Summary: raw.Summary,
+	}
+	m.CreatedAt, _ = time.Parse(time.RFC3339, raw.CreatedAt)
+
+	content, err := json.Marshal(raw.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal meeting content: %w", err)
+	}
+	var t transcript
+	if err := json.Unmarshal(content, &t); err != nil {
+		return m, nil
+	}
+	for _, c := range t.Contents {
+		m.Utterances = append(m.Utterances, Utterance{
+			From:    c.TimeFrom,
+			To:      c.TimeTo,
+			Speaker: c.User,
+			Text:    c.Content.Text,
+		})
+	}
+	return m, nil
+}
+
+// ParseOffset 解析会议内的时间偏移，支持 HH:MM:SS、MM:SS 与秒数
+func ParseOffset(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty time offset")
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("invalid time offset: %s", s)
+	}
+	var seconds float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("invalid time offset: %s", s)
+		}
+		seconds = seconds*60 + v
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
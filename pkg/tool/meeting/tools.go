@@ -0,0 +1,398 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package meeting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+)
+
+type ToolConfig struct {
+	Repository *Repository
+}
+
+type toolImpl struct {
+	repo *Repository
+}
+
+// NewTools 创建会议相关的工具：list_meetings、get_meeting_summary、get_transcript_segment、
+// search_meetings 与 get_speaker_stats
+func NewTools(ctx context.Context, config *ToolConfig) ([]tool.BaseTool, error) {
+	if config == nil {
+		config = &ToolConfig{}
+	}
+	if config.Repository == nil {
+		config.Repository = NewRepository(nil)
+	}
+	t := &toolImpl{repo: config.Repository}
+
+	listTool, err := utils.InferTool("list_meetings",
+		"list meetings, newest first. use date_from/date_to (YYYY-MM-DD, inclusive) to find meetings held on a given day, e.g. \"last Tuesday\"",
+		t.ListMeetings)
+	if err != nil {
+		return nil, err
+	}
+	summaryTool, err := utils.InferTool("get_meeting_summary",
+		"get the generated summary of a meeting by meeting id",
+		t.GetMeetingSummary)
+	if err != nil {
+		return nil, err
+	}
+	segmentTool, err := utils.InferTool("get_transcript_segment",
+		"get the verbatim transcript of a meeting between two time offsets (HH:MM:SS), optionally only one speaker",
+		t.GetTranscriptSegment)
+	if err != nil {
+		return nil, err
+	}
+	searchTool, err := utils.InferTool("search_meetings",
+		"search meeting transcripts for an exact keyword or phrase, returns the matching utterances with meeting id and time offsets",
+		t.SearchMeetings)
+	if err != nil {
+		return nil, err
+	}
+	speakerTool, err := utils.InferTool("get_speaker_stats",
+		"get per speaker statistics (number of utterances, speaking time, characters) of one meeting or of all meetings in a date range",
+		t.GetSpeakerStats)
+	if err != nil {
+		return nil, err
+	}
+
+	return []tool.BaseTool{listTool, summaryTool, segmentTool, searchTool, speakerTool}, nil
+}
+
+type DateRange struct {
+	DateFrom string `json:"date_from,omitempty" jsonschema:"description=only meetings held on or after this date, YYYY-MM-DD"`
+	DateTo   string `json:"date_to,omitempty" jsonschema:"description=only meetings held on or before this date, YYYY-MM-DD"`
+}
+
+// filter 返回日期范围内的会议
+func (r *DateRange) filter(meetings []*Meeting) ([]*Meeting, error) {
+	from, to, err := r.parse()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Meeting, 0, len(meetings))
+	for _, m := range meetings {
+		created := m.CreatedAt.In(time.Local)
+		if !from.IsZero() && created.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !created.Before(to) {
+			continue
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+func (r *DateRange) parse() (from, to time.Time, err error) {
+	if r.DateFrom != "" {
+		if from, err = time.ParseInLocation("2006-01-02", r.DateFrom, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid date_from: %s", r.DateFrom)
+		}
+	}
+	if r.DateTo != "" {
+		if to, err = time.ParseInLocation("2006-01-02", r.DateTo, time.Local); err != nil {
+			return from, to, fmt.Errorf("invalid date_to: %s", r.DateTo)
+		}
+		// 包含当天
+		to = to.AddDate(0, 0, 1)
+	}
+	return from, to, nil
+}
+
+type ListMeetingsRequest struct {
+	DateRange
+	Limit int `json:"limit,omitempty" jsonschema:"description=max number of meetings to return, default 20"`
+}
+
+type MeetingBrief struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title,omitempty"`
+	CreatedAt string   `json:"created_at"`
+	Weekday   string   `json:"weekday"`
+	Duration  string   `json:"duration"`
+	Speakers  []string `json:"speakers"`
+}
+
+type ListMeetingsResponse struct {
+	Meetings []*MeetingBrief `json:"meetings"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func (t *toolImpl) ListMeetings(ctx context.Context, req *ListMeetingsRequest) (*ListMeetingsResponse, error) {
+	res := &ListMeetingsResponse{Meetings: []*MeetingBrief{}}
+	meetings, err := t.repo.List(ctx)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	meetings, err = req.filter(meetings)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	for i, m := range meetings {
+		if i >= limit {
+			break
+		}
+		created := m.CreatedAt.In(time.Local)
+		res.Meetings = append(res.Meetings, &MeetingBrief{
+			ID:        m.ID,
+			Title:     m.Title(),
+			CreatedAt: created.Format("2006-01-02 15:04"),
+			Weekday:   created.Weekday().String(),
+			Duration:  m.Duration().String(),
+			Speakers:  m.Speakers(),
+		})
+	}
+	return res, nil
+}
+
+type GetMeetingSummaryRequest struct {
+	MeetingID string `json:"meeting_id" jsonschema:"description=id of the meeting,required"`
+}
+
+type GetMeetingSummaryResponse struct {
+	MeetingID string `json:"meeting_id"`
+	CreatedAt string `json:"created_at,omitempty"`
+	Summary   string `json:"summary,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (t *toolImpl) GetMeetingSummary(ctx context.Context, req *GetMeetingSummaryRequest) (*GetMeetingSummaryResponse, error) {
+	res := &GetMeetingSummaryResponse{MeetingID: req.MeetingID}
+	m, err := t.get(ctx, req.MeetingID)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.CreatedAt = m.CreatedAt.In(time.Local).Format("2006-01-02 15:04")
+	res.Summary = m.Summary
+	return res, nil
+}
+
+type GetTranscriptSegmentRequest struct {
+	MeetingID string `json:"meeting_id" jsonschema:"description=id of the meeting,required"`
+	From      string `json:"from,omitempty" jsonschema:"description=start offset in the meeting, HH:MM:SS, default start of meeting"`
+	To        string `json:"to,omitempty" jsonschema:"description=end offset in the meeting, HH:MM:SS, default end of meeting"`
+	Speaker   string `json:"speaker,omitempty" jsonschema:"description=only return utterances of this speaker"`
+}
+
+type TranscriptResponse struct {
+	Utterances []*MatchedUtterance `json:"utterances"`
+	Truncated  bool                `json:"truncated,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
+
+type MatchedUtterance struct {
+	MeetingID string `json:"meeting_id"`
+	Utterance
+}
+
+// maxUtterances 单次返回的最大发言数，避免撑爆上下文
+const maxUtterances = 60
+
+func (t *toolImpl) GetTranscriptSegment(ctx context.Context, req *GetTranscriptSegmentRequest) (*TranscriptResponse, error) {
+	res := &TranscriptResponse{Utterances: []*MatchedUtterance{}}
+	m, err := t.get(ctx, req.MeetingID)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	from, to := time.Duration(0), time.Duration(-1)
+	if req.From != "" {
+		if from, err = ParseOffset(req.From); err != nil {
+			res.Error = err.Error()
+			return res, nil
+		}
+	}
+	if req.To != "" {
+		if to, err = ParseOffset(req.To); err != nil {
+			res.Error = err.Error()
+			return res, nil
+		}
+	}
+
+	for _, u := range m.Utterances {
+		if req.Speaker != "" && !strings.EqualFold(u.Speaker, req.Speaker) {
+			continue
+		}
+		// 与时间范围有重叠的发言都返回
+		start, _ := ParseOffset(u.From)
+		end, err := ParseOffset(u.To)
+		if err != nil {
+			end = start
+		}
+		if end < from || (to >= 0 && start > to) {
+			continue
+		}
+		if len(res.Utterances) >= maxUtterances {
+			res.Truncated = true
+			break
+		}
+		res.Utterances = append(res.Utterances, &MatchedUtterance{MeetingID: m.ID, Utterance: u})
+	}
+	return res, nil
+}
+
+type SearchMeetingsRequest struct {
+	Query     string `json:"query" jsonschema:"description=keyword or phrase to search for, case insensitive,required"`
+	MeetingID string `json:"meeting_id,omitempty" jsonschema:"description=only search this meeting"`
+	Speaker   string `json:"speaker,omitempty" jsonschema:"description=only search utterances of this speaker"`
+	DateRange
+	Limit int `json:"limit,omitempty" jsonschema:"description=max number of utterances to return, default 20"`
+}
+
+func (t *toolImpl) SearchMeetings(ctx context.Context, req *SearchMeetingsRequest) (*TranscriptResponse, error) {
+	res := &TranscriptResponse{Utterances: []*MatchedUtterance{}}
+	query := strings.ToLower(strings.TrimSpace(req.Query))
+	if query == "" {
+		res.Error = "query is required"
+		return res, nil
+	}
+
+	meetings, err := t.meetings(ctx, req.MeetingID, &req.DateRange)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+
+	limit := req.Limit
+	if limit <= 0 || limit > maxUtterances {
+		limit = 20
+	}
+	for _, m := range meetings {
+		for _, u := range m.Utterances {
+			if req.Speaker != "" && !strings.EqualFold(u.Speaker, req.Speaker) {
+				continue
+			}
+			if !strings.Contains(strings.ToLower(u.Text), query) {
+				continue
+			}
+			if len(res.Utterances) >= limit {
+				res.Truncated = true
+				return res, nil
+			}
+			res.Utterances = append(res.Utterances, &MatchedUtterance{MeetingID: m.ID, Utterance: u})
+		}
+	}
+	return res, nil
+}
+
+type GetSpeakerStatsRequest struct {
+	MeetingID string `json:"meeting_id,omitempty" jsonschema:"description=id of the meeting, empty means all meetings in the date range"`
+	DateRange
+}
+
+type SpeakerStats struct {
+	Speaker      string  `json:"speaker"`
+	Utterances   int     `json:"utterances"`
+	SpeakingTime string  `json:"speaking_time"`
+	Characters   int     `json:"characters"`
+	Share        float64 `json:"share" jsonschema:"description=share of total speaking time, 0-1"`
+
+	seconds float64
+}
+
+type GetSpeakerStatsResponse struct {
+	Meetings int             `json:"meetings"`
+	Speakers []*SpeakerStats `json:"speakers"`
+	Error    string          `json:"error,omitempty"`
+}
+
+func (t *toolImpl) GetSpeakerStats(ctx context.Context, req *GetSpeakerStatsRequest) (*GetSpeakerStatsResponse, error) {
+	res := &GetSpeakerStatsResponse{Speakers: []*SpeakerStats{}}
+	meetings, err := t.meetings(ctx, req.MeetingID, &req.DateRange)
+	if err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.Meetings = len(meetings)
+
+	stats := make(map[string]*SpeakerStats)
+	var total float64
+	for _, m := range meetings {
+		for _, u := range m.Utterances {
+			s, ok := stats[u.Speaker]
+			if !ok {
+				s = &SpeakerStats{Speaker: u.Speaker}
+				stats[u.Speaker] = s
+				res.Speakers = append(res.Speakers, s)
+			}
+			s.Utterances++
+			s.Characters += len([]rune(u.Text))
+			start, err1 := ParseOffset(u.From)
+			end, err2 := ParseOffset(u.To)
+			if err1 == nil && err2 == nil && end > start {
+				s.seconds += (end - start).Seconds()
+				total += (end - start).Seconds()
+			}
+		}
+	}
+
+	for _, s := range res.Speakers {
+		s.SpeakingTime = (time.Duration(s.seconds) * time.Second).String()
+		if total > 0 {
+			s.Share = float64(int(s.seconds/total*1000)) / 1000
+		}
+	}
+	sort.SliceStable(res.Speakers, func(i, j int) bool {
+		return res.Speakers[i].seconds > res.Speakers[j].seconds
+	})
+	return res, nil
+}
+
+func (t *toolImpl) get(ctx context.Context, id string) (*Meeting, error) {
+	if id == "" {
+		return nil, fmt.Errorf("meeting_id is required")
+	}
+	m, err := t.repo.Get(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("%w, call list_meetings to find valid ids", err)
+	}
+	return m, err
+}
+
+// meetings meetingID 不为空时只返回该会议，否则返回日期范围内的全部会议
+func (t *toolImpl) meetings(ctx context.Context, meetingID string, dates *DateRange) ([]*Meeting, error) {
+	if meetingID != "" {
+		m, err := t.get(ctx, meetingID)
+		if err != nil {
+			return nil, err
+		}
+		return []*Meeting{m}, nil
+	}
+	meetings, err := t.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dates.filter(meetings)
+}
@@ -0,0 +1,339 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cloudwego/eino/components/embedding"
+
+	"meetingagent/pkg/embedder"
+	"meetingagent/pkg/env"
+)
+
+// ErrDuplicate 新增的任务与已有的未完成任务相似，DuplicateAsk 时不添加
+var ErrDuplicate = errors.New("likely duplicate of an existing task")
+
+// DuplicatePolicy 新增的任务与已有的未完成任务相似时的处理方式
+type DuplicatePolicy string
+
+const (
+	// DuplicateMerge 不新建，把新任务中已有任务缺少的信息合并进去
+	DuplicateMerge DuplicatePolicy = "merge"
+	// DuplicateSkip 不新建，直接返回已有任务
+	DuplicateSkip DuplicatePolicy = "skip"
+	// DuplicateAsk 不新建，把相似的任务返回给 agent，确认不是重复后以 allow_duplicates 重新添加
+	DuplicateAsk DuplicatePolicy = "ask"
+	// DuplicateOff 不查重
+	DuplicateOff DuplicatePolicy = "off"
+)
+
+func (p DuplicatePolicy) Valid() bool {
+	switch p {
+	case DuplicateMerge, DuplicateSkip, DuplicateAsk, DuplicateOff:
+		return true
+	}
+	return false
+}
+
+// DuplicateConfig 新增任务时的查重配置
+type DuplicateConfig struct {
+	Policy DuplicatePolicy
+	// Threshold 标题与内容向量的余弦相似度达到该值时视为重复
+	Threshold float64
+	// Embedding 为 nil 时只比较规范化后的标题
+	Embedding embedding.Embedder
+	// MaxCandidates 参与向量比较的已有任务数量上限，取最近修改的，0 表示不限制
+	MaxCandidates int
+	// Timeout 向量化的超时，超时后只比较标题，0 表示不限制
+	Timeout time.Duration
+}
+
+// duplicateConfigFromEnv 按环境变量创建：
+//   - TASK_DUPLICATE_POLICY: merge、skip、ask 或 off，默认 ask
+//   - TASK_DUPLICATE_THRESHOLD: 相似度阈值，默认 0.9
+//   - TASK_DUPLICATE_MAX_CANDIDATES: 参与向量比较的最近修改的任务数，默认 50
+//   - TASK_DUPLICATE_TIMEOUT: 向量化的超时，默认 5s
+//
+// 向量化模型与检索相同（见 embedder.New），未配置 ARK_EMBEDDING_MODEL 时只比较标题
+func duplicateConfigFromEnv(ctx context.Context) (*DuplicateConfig, error) {
+	config := &DuplicateConfig{
+		Policy:        DuplicatePolicy(env.GetString("TASK_DUPLICATE_POLICY", string(DuplicateAsk))),
+		Threshold:     env.GetFloat("TASK_DUPLICATE_THRESHOLD", 0.9),
+		MaxCandidates: env.GetInt("TASK_DUPLICATE_MAX_CANDIDATES", 50),
+		Timeout:       env.GetDuration("TASK_DUPLICATE_TIMEOUT", 5*time.Second),
+	}
+	if !config.Policy.Valid() {
+		return nil, fmt.Errorf("invalid TASK_DUPLICATE_POLICY: %s", config.Policy)
+	}
+	if config.Policy == DuplicateOff || env.GetString("ARK_EMBEDDING_MODEL", "") == "" {
+		return config, nil
+	}
+	eb, err := embedder.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init embedding for duplicate detection: %w", err)
+	}
+	config.Embedding = eb
+	return config, nil
+}
+
+// DuplicateCandidate 与新任务相似的已有任务
+type DuplicateCandidate struct {
+	Task *Task `json:"task"`
+	// Score 相似度，规范化后标题相同时为 1
+	Score float64 `json:"score"`
+}
+
+// Duplicate 一个被判定为重复的新任务，以及对它的处理
+type Duplicate struct {
+	// Title 与 Ref 为请求中的新任务
+	Title string `json:"title"`
+	Ref   string `json:"ref,omitempty"`
+	// Action merged、skipped 或 candidate（未添加，等待确认）
+	Action     string                `json:"action"`
+	Candidates []*DuplicateCandidate `json:"candidates"`
+}
+
+const (
+	DuplicateActionMerged    = "merged"
+	DuplicateActionSkipped   = "skipped"
+	DuplicateActionCandidate = "candidate"
+)
+
+// maxDuplicateCandidates 返回给 agent 的相似任务数量上限
+const maxDuplicateCandidates = 3
+
+// FindDuplicates 查找与 task 相似的未完成任务，按相似度从高到低排列。
+// 负责人都已填写且不同的任务不视为重复；标题与全部任务比较，向量只与最近修改的 MaxCandidates 个任务比较，
+// 向量化失败或超时时只比较标题
+func (s *Service) FindDuplicates(ctx context.Context, task *Task) ([]*DuplicateCandidate, error) {
+	config := s.config.Duplicates
+	if config == nil || config.Policy == DuplicateOff {
+		return nil, nil
+	}
+	open, err := s.config.Storage.List(&ListParams{
+		Status: []Status{StatusTodo, StatusInProgress, StatusBlocked},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	title := normalizeTitle(task.Title)
+	var candidates []*DuplicateCandidate
+	var rest []*Task
+	for _, existing := range open {
+		if existing.ID == task.ID ||
+			(task.Assignee != "" && existing.Assignee != "" && !strings.EqualFold(task.Assignee, existing.Assignee)) {
+			continue
+		}
+		if title != "" && normalizeTitle(existing.Title) == title {
+			candidates = append(candidates, &DuplicateCandidate{Task: existing, Score: 1})
+			continue
+		}
+		rest = append(rest, existing)
+	}
+
+	if config.Embedding != nil && len(rest) > 0 {
+		// 只比较最近修改的任务，避免每次添加都为全部任务计算向量
+		if config.MaxCandidates > 0 && len(rest) > config.MaxCandidates {
+			sort.SliceStable(rest, func(i, j int) bool {
+				return rest[i].UpdatedAt > rest[j].UpdatedAt
+			})
+			rest = rest[:config.MaxCandidates]
+		}
+		embedCtx := ctx
+		if config.Timeout > 0 {
+			var cancel context.CancelFunc
+			embedCtx, cancel = context.WithTimeout(ctx, config.Timeout)
+			defer cancel()
+		}
+		similar, err := similarTasks(embedCtx, config.Embedding, task, rest, config.Threshold)
+		if err != nil {
+			log.Printf("[task] duplicate detection falls back to titles: %v", err)
+		}
+		candidates = append(candidates, similar...)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > maxDuplicateCandidates {
+		candidates = candidates[:maxDuplicateCandidates]
+	}
+	return candidates, nil
+}
+
+// similarTasks 比较标题与内容的向量，返回相似度达到 threshold 的任务
+func similarTasks(ctx context.Context, eb embedding.Embedder, task *Task, others []*Task, threshold float64) ([]*DuplicateCandidate, error) {
+	texts := make([]string, 0, len(others)+1)
+	texts = append(texts, duplicateText(task))
+	for _, other := range others {
+		texts = append(texts, duplicateText(other))
+	}
+	vectors, err := eb.EmbedStrings(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("expected %d vectors, got %d", len(texts), len(vectors))
+	}
+
+	var candidates []*DuplicateCandidate
+	for i, other := range others {
+		if score := cosine(vectors[0], vectors[i+1]); score >= threshold {
+			candidates = append(candidates, &DuplicateCandidate{Task: other, Score: score})
+		}
+	}
+	return candidates, nil
+}
+
+func duplicateText(task *Task) string {
+	return strings.TrimSpace(task.Title + "\n" + task.Content)
+}
+
+func cosine(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += a[i] * b[i]
+		na += a[i] * a[i]
+		nb += b[i] * b[i]
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// normalizeTitle 转为小写并去掉标点与空白，"Write the spec." 与 "write the spec" 相同
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// createUnique 添加任务前查重，按 Policy 处理相似的任务：
+//   - merge、skip: 返回已有任务与处理结果
+//   - ask: 返回 ErrDuplicate 与相似的任务
+//
+// 没有相似任务时正常添加，返回的 Duplicate 为 nil
+func (s *Service) createUnique(ctx context.Context, task *Task) (*Task, *Duplicate, error) {
+	candidates, err := s.FindDuplicates(ctx, task)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(candidates) == 0 {
+		created, err := s.create(ctx, task)
+		return created, nil, err
+	}
+
+	dup := &Duplicate{Title: task.Title, Ref: task.Ref, Candidates: candidates}
+	best := candidates[0].Task
+	switch s.config.Duplicates.Policy {
+	case DuplicateMerge:
+		dup.Action = DuplicateActionMerged
+		merged, err := s.merge(ctx, best, task)
+		return merged, dup, err
+	case DuplicateSkip:
+		dup.Action = DuplicateActionSkipped
+		s.rollup(best)
+		return best, dup, nil
+	default:
+		dup.Action = DuplicateActionCandidate
+		return nil, dup, fmt.Errorf("%w %s (%s), update it instead or add again with allow_duplicates",
+			ErrDuplicate, best.ID, best.Title)
+	}
+}
+
+// merge 把 task 中 existing 缺少的信息合并到 existing：填写空的字段，合并标签与前置任务，
+// 内容不同时追加在后面，优先级取较高的一个。没有需要合并的信息时不修改
+func (s *Service) merge(ctx context.Context, existing *Task, task *Task) (*Task, error) {
+	// 相对截止时间按新任务的来源会议换算
+	s.resolveDeadline(ctx, task, task.SourceMeetingID)
+
+	patch := &Task{ID: existing.ID}
+	changed := false
+	if task.Content != "" && !strings.Contains(existing.Content, task.Content) {
+		patch.Content = strings.TrimSpace(existing.Content + "\n\n" + task.Content)
+		changed = true
+	}
+	if existing.Deadline == "" && task.Deadline != "" {
+		patch.Deadline, patch.DeadlineText = task.Deadline, task.DeadlineText
+		changed = true
+	}
+	if existing.Assignee == "" && task.Assignee != "" {
+		patch.Assignee = task.Assignee
+		changed = true
+	}
+	if priorityRank(task.Priority) > priorityRank(existing.Priority) {
+		patch.Priority = task.Priority
+		changed = true
+	}
+	if existing.SourceMeetingID == "" && task.SourceMeetingID != "" {
+		patch.SourceMeetingID, patch.SourceTimestamp = task.SourceMeetingID, task.SourceTimestamp
+		changed = true
+	}
+	if existing.ParentID == "" && task.ParentID != "" {
+		patch.ParentID = task.ParentID
+		changed = true
+	}
+	if tags := union(existing.Tags, task.Tags); len(tags) > len(existing.Tags) {
+		patch.Tags = tags
+		changed = true
+	}
+	if blockedBy := union(existing.BlockedBy, task.BlockedBy); len(blockedBy) > len(existing.BlockedBy) {
+		patch.BlockedBy = blockedBy
+		changed = true
+	}
+	if !changed {
+		s.rollup(existing)
+		return existing, nil
+	}
+	return s.update(ctx, existing.ID, ETag(existing), func(*Task) *Task {
+		return patch
+	})
+}
+
+func priorityRank(p Priority) int {
+	return slices.Index([]Priority{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}, p)
+}
+
+// union 保持 a 的顺序，追加 b 中不在 a 里的元素
+func union(a, b []string) []string {
+	result := slices.Clone(a)
+	for _, s := range b {
+		if !slices.Contains(result, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
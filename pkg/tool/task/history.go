@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// ErrNotDeleted 恢复的任务没有被删除
+var ErrNotDeleted = errors.New("task is not deleted")
+
+// ChangeAction 任务变更的类型
+type ChangeAction string
+
+const (
+	ChangeCreate  ChangeAction = "create"
+	ChangeUpdate  ChangeAction = "update"
+	ChangeDelete  ChangeAction = "delete"
+	ChangeRestore ChangeAction = "restore"
+	ChangePurge   ChangeAction = "purge"
+)
+
+// ActorKind 变更的来源
+type ActorKind string
+
+const (
+	// ActorUser 通过 /tasks 接口或页面修改，ID 为 X-User 请求头
+	ActorUser ActorKind = "user"
+	// ActorAgent agent 调用 task_manager 工具修改，ID 为对话 ID
+	ActorAgent ActorKind = "agent"
+	// ActorImport 从会议导入的任务，ID 为来源会议 ID
+	ActorImport ActorKind = "import"
+	// ActorSync 从外部任务系统拉取的状态变化，ID 为连接器名称
+	ActorSync ActorKind = "sync"
+	// ActorSystem ctx 中没有指定来源时使用
+	ActorSystem ActorKind = "system"
+)
+
+type Actor struct {
+	Kind ActorKind `json:"kind"`
+	ID   string    `json:"id,omitempty"`
+}
+
+type actorKey struct{}
+
+// WithActor 指定 ctx 中任务变更的来源，记录在变更历史中
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom 返回 ctx 中的来源，没有时为 ActorSystem
+func ActorFrom(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{Kind: ActorSystem}
+}
+
+// Change 任务的一次变更，只追加不修改。Before 为 nil 表示新建，After 为 nil 表示彻底删除
+type Change struct {
+	TaskID string       `json:"task_id"`
+	Action ChangeAction `json:"action"`
+	Actor  Actor        `json:"actor"`
+	// Fields 发生变化的字段，不包括 updated_at，新建与彻底删除时为空
+	Fields    []string `json:"fields,omitempty"`
+	Before    *Task    `json:"before,omitempty"`
+	After     *Task    `json:"after,omitempty"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// newChange 创建一条变更记录，从会议导入且没有指定会议时使用任务的来源会议
+func newChange(ctx context.Context, action ChangeAction, before, after *Task) *Change {
+	actor := ActorFrom(ctx)
+	change := &Change{
+		Action:    action,
+		Actor:     actor,
+		Before:    before,
+		After:     after,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	if before != nil && after != nil {
+		change.Fields = changedFields(before, after)
+	}
+	for _, t := range []*Task{after, before} {
+		if t == nil {
+			continue
+		}
+		change.TaskID = t.ID
+		if actor.Kind == ActorImport && change.Actor.ID == "" {
+			change.Actor.ID = t.SourceMeetingID
+		}
+		break
+	}
+	return change
+}
+
+// changedFields 按 json 字段名比较两个版本，返回变化的字段
+func changedFields(before, after *Task) []string {
+	a, b := taskFields(before), taskFields(after)
+	for name := range a {
+		if _, ok := b[name]; !ok {
+			b[name] = nil
+		}
+	}
+	var fields []string
+	for name, value := range b {
+		if name != "updated_at" && !reflect.DeepEqual(a[name], value) {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func taskFields(task *Task) map[string]any {
+	fields := make(map[string]any)
+	data, _ := json.Marshal(task)
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+func decodeChange(data string) (*Change, error) {
+	var change Change
+	if err := json.Unmarshal([]byte(data), &change); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
@@ -0,0 +1,432 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// JSONLStore 将任务保存在 tasks.jsonl 中，全部任务缓存在内存里，适合单实例部署。
+// 变更历史追加到 task_history.jsonl，查询时读取文件
+type JSONLStore struct {
+	filePath    string
+	historyPath string
+	mu          sync.RWMutex
+	tasks       taskMap
+}
+
+func NewJSONLStore(dataDir string) (*JSONLStore, error) {
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	s := &JSONLStore{
+		filePath:    filepath.Join(dataDir, "tasks.jsonl"),
+		historyPath: filepath.Join(dataDir, "task_history.jsonl"),
+	}
+
+	tasks, err := ReadJSONL(s.filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load from disk: %w", err)
+	}
+	s.tasks = make(taskMap, len(tasks))
+	for _, task := range tasks {
+		s.tasks[task.ID] = task
+	}
+	return s, nil
+}
+
+// ReadJSONL 读取 tasks.jsonl 中的全部任务，包括已删除的，文件不存在时返回空列表
+func ReadJSONL(filePath string) ([]*Task, error) {
+	file, err := os.Open(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	var tasks []*Task
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var task Task
+		if err := json.Unmarshal(scanner.Bytes(), &task); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+		}
+		task.normalize()
+		tasks = append(tasks, &task)
+	}
+	return tasks, scanner.Err()
+}
+
+func (s *JSONLStore) Add(task *Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prepareAdd(task)
+	s.tasks[task.ID] = task
+
+	// 直接追加到文件末尾
+	file, err := os.OpenFile(s.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *JSONLStore) Get(id string) (*Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tasks.Get(id)
+}
+
+func (s *JSONLStore) GetDeleted(id string) (*Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tasks.GetDeleted(id)
+}
+
+func (s *JSONLStore) List(params *ListParams) ([]*Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tasks.List(params)
+}
+
+func (s *JSONLStore) Update(task *Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Update(task)
+	})
+}
+
+func (s *JSONLStore) Delete(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Delete(id)
+	})
+}
+
+func (s *JSONLStore) Restore(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Restore(id)
+	})
+}
+
+func (s *JSONLStore) Purge(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Purge(id)
+	})
+}
+
+func (s *JSONLStore) AppendHistory(change *Change) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return appendHistory(s.historyPath, []*Change{change})
+}
+
+func (s *JSONLStore) History(id string) ([]*Change, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return ReadHistoryJSONL(s.historyPath, id)
+}
+
+func (s *JSONLStore) Import(tasks []*Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Import(tasks)
+	})
+}
+
+// Tx 在任务的副本上执行 fn，成功后整体写回文件，再追加其中的变更记录
+func (s *JSONLStore) Tx(fn func(tx TaskStore) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	staged := make(taskMap, len(s.tasks))
+	for id, task := range s.tasks {
+		copied := *task
+		staged[id] = &copied
+	}
+	tx := &jsonlTx{taskMap: staged, historyPath: s.historyPath}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := syncToDisk(s.filePath, staged); err != nil {
+		return err
+	}
+	s.tasks = staged
+	return appendHistory(s.historyPath, tx.history)
+}
+
+func (s *JSONLStore) Close() error {
+	return nil
+}
+
+// taskMap 内存中的任务，JSONLStore 的事务直接操作它，不涉及文件与锁
+type taskMap map[string]*Task
+
+func (m taskMap) Add(task *Task) error {
+	prepareAdd(task)
+	m[task.ID] = task
+	return nil
+}
+
+func (m taskMap) Get(id string) (*Task, error) {
+	task, exists := m[id]
+	if !exists || task.IsDeleted {
+		return nil, notFound(id)
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (m taskMap) Update(task *Task) error {
+	existing, exists := m[task.ID]
+	if !exists || existing.IsDeleted {
+		return notFound(task.ID)
+	}
+	m[task.ID] = applyUpdate(existing, task)
+	return nil
+}
+
+func (m taskMap) Delete(id string) error {
+	task, exists := m[id]
+	if !exists || task.IsDeleted {
+		return notFound(id)
+	}
+	// 标记删除
+	task.IsDeleted = true
+	return nil
+}
+
+func (m taskMap) GetDeleted(id string) (*Task, error) {
+	task, exists := m[id]
+	if !exists || !task.IsDeleted {
+		return nil, notFound(id)
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (m taskMap) Restore(id string) error {
+	task, exists := m[id]
+	if !exists {
+		return notFound(id)
+	}
+	if !task.IsDeleted {
+		return fmt.Errorf("%w: %s", ErrNotDeleted, id)
+	}
+	m[id] = restored(task)
+	return nil
+}
+
+func (m taskMap) Purge(id string) error {
+	if _, exists := m[id]; !exists {
+		return notFound(id)
+	}
+	delete(m, id)
+	return nil
+}
+
+func (m taskMap) List(params *ListParams) ([]*Task, error) {
+	var tasks []*Task
+	for _, task := range m {
+		if matches(task, params) {
+			copied := *task
+			tasks = append(tasks, &copied)
+		}
+	}
+	return sortAndLimit(tasks, params), nil
+}
+
+func (m taskMap) Import(tasks []*Task) error {
+	for _, task := range tasks {
+		copied := *task
+		copied.normalize()
+		m[task.ID] = &copied
+	}
+	return nil
+}
+
+func (m taskMap) Tx(fn func(tx TaskStore) error) error {
+	return fmt.Errorf("nested transaction is not supported")
+}
+
+func (m taskMap) Close() error {
+	return nil
+}
+
+// jsonlTx JSONLStore 的事务，变更记录先缓存，任务写回文件后再追加
+type jsonlTx struct {
+	taskMap
+	historyPath string
+	history     []*Change
+}
+
+func (t *jsonlTx) AppendHistory(change *Change) error {
+	t.history = append(t.history, change)
+	return nil
+}
+
+func (t *jsonlTx) History(id string) ([]*Change, error) {
+	changes, err := ReadHistoryJSONL(t.historyPath, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range t.history {
+		if change.TaskID == id {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// appendHistory 将变更记录追加到文件末尾
+func appendHistory(path string, changes []*Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+	var buf []byte
+	for _, change := range changes {
+		data, err := json.Marshal(change)
+		if err != nil {
+			return fmt.Errorf("failed to marshal change: %w", err)
+		}
+		buf = append(append(buf, data...), '\n')
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(buf); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync history file: %w", err)
+	}
+	return nil
+}
+
+// ReadHistoryJSONL 读取任务 id 的变更记录，id 为空时读取全部，文件不存在时返回空列表
+func ReadHistoryJSONL(path string, id string) ([]*Change, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var changes []*Change
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		// 先按 task_id 粗略过滤，避免解析全部记录
+		if len(line) == 0 || (id != "" && !bytes.Contains(line, []byte(id))) {
+			continue
+		}
+		change, err := decodeChange(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal change: %w", err)
+		}
+		if id == "" || change.TaskID == id {
+			changes = append(changes, change)
+		}
+	}
+	return changes, scanner.Err()
+}
+
+// syncToDisk 按创建时间顺序重写整个文件，先写临时文件再替换，写入失败时原文件不变
+func syncToDisk(filePath string, tasks taskMap) error {
+	ordered := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		ordered = append(ordered, task)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		if ordered[i].CreatedAt != ordered[j].CreatedAt {
+			return ordered[i].CreatedAt < ordered[j].CreatedAt
+		}
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	// 创建临时文件
+	tmpFile := filePath + ".tmp"
+	file, err := os.Create(tmpFile)
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, task := range ordered {
+		data, err := json.Marshal(task)
+		if err != nil {
+			os.Remove(tmpFile) // 清理临时文件
+			return fmt.Errorf("failed to marshal task: %w", err)
+		}
+		w.Write(data)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+
+	// 确保所有数据都写入磁盘
+	if err := file.Sync(); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+
+	// rename 是原子的，不需要备份原文件
+	if err := os.Rename(tmpFile, filePath); err != nil {
+		os.Remove(tmpFile)
+		return fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,465 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisKeyPrefix = "eino:task:"
+	// redisIDsKey 未删除任务的 ID 集合
+	redisIDsKey = redisKeyPrefix + "ids"
+	// redisDeletedKey 已删除任务的 ID 集合
+	redisDeletedKey = redisKeyPrefix + "deleted"
+	// redisTxRetries 事务因其他实例并发修改而失败时的重试次数
+	redisTxRetries = 10
+)
+
+func redisItemKey(id string) string {
+	return redisKeyPrefix + "item:" + id
+}
+
+// redisHistoryKey 任务的变更历史，每条记录是列表中的一个 json 字符串
+func redisHistoryKey(id string) string {
+	return redisKeyPrefix + "history:" + id
+}
+
+func redisIndexKey(field, value string) string {
+	return redisKeyPrefix + "index:" + field + ":" + value
+}
+
+// redisIndexKeys 任务所在的索引集合，已删除的任务只在 redisDeletedKey 中
+func redisIndexKeys(task *Task) []string {
+	if task == nil {
+		return nil
+	}
+	if task.IsDeleted {
+		return []string{redisDeletedKey}
+	}
+	keys := []string{
+		redisIDsKey,
+		redisIndexKey("status", string(task.Status)),
+		redisIndexKey("priority", string(task.Priority)),
+	}
+	if task.Assignee != "" {
+		keys = append(keys, redisIndexKey("assignee", strings.ToLower(task.Assignee)))
+	}
+	if task.SourceMeetingID != "" {
+		keys = append(keys, redisIndexKey("meeting", task.SourceMeetingID))
+	}
+	for _, tag := range task.Tags {
+		keys = append(keys, redisIndexKey("tag", strings.ToLower(tag)))
+	}
+	return keys
+}
+
+// RedisStore 每个任务保存为一个 json 字符串，另按状态、负责人等维护 ID 集合作为索引，
+// 多个实例可以共享同一份任务
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(addr string) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return &RedisStore{client: client}, nil
+}
+
+func (s *RedisStore) Add(task *Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Add(task)
+	})
+}
+
+func (s *RedisStore) Get(id string) (*Task, error) {
+	return newRedisTx(s.client, nil).Get(id)
+}
+
+func (s *RedisStore) Update(task *Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Update(task)
+	})
+}
+
+func (s *RedisStore) Delete(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Delete(id)
+	})
+}
+
+func (s *RedisStore) GetDeleted(id string) (*Task, error) {
+	return newRedisTx(s.client, nil).GetDeleted(id)
+}
+
+func (s *RedisStore) Restore(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Restore(id)
+	})
+}
+
+func (s *RedisStore) Purge(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Purge(id)
+	})
+}
+
+func (s *RedisStore) List(params *ListParams) ([]*Task, error) {
+	return newRedisTx(s.client, nil).List(params)
+}
+
+func (s *RedisStore) AppendHistory(change *Change) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.AppendHistory(change)
+	})
+}
+
+func (s *RedisStore) History(id string) ([]*Change, error) {
+	return newRedisTx(s.client, nil).History(id)
+}
+
+func (s *RedisStore) Import(tasks []*Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Import(tasks)
+	})
+}
+
+// Tx 读取过的 key 都会被 WATCH，写入缓存到最后在 MULTI 中一起提交；
+// 期间有其他实例修改了这些 key 时重新执行 fn
+func (s *RedisStore) Tx(fn func(tx TaskStore) error) error {
+	ctx := context.Background()
+	for i := 0; i < redisTxRetries; i++ {
+		err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+			t := newRedisTx(tx, tx)
+			if err := fn(t); err != nil {
+				return err
+			}
+			return t.commit(ctx)
+		})
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+		// 随机等待后重试，避免多个实例同时重试再次冲突
+		time.Sleep(time.Duration(rand.Int64N(int64(i+1) * int64(10*time.Millisecond))))
+	}
+	return fmt.Errorf("failed to commit transaction: too many concurrent updates")
+}
+
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
+
+// redisTx 读取直接访问 redis，写入先缓存在 staged 中。tx 为 nil 时只用于读取
+type redisTx struct {
+	cmd redis.Cmdable
+	tx  *redis.Tx
+	// loaded 读取到的原始任务，提交时据此移除旧的索引，nil 表示不存在
+	loaded map[string]*Task
+	// staged 待写入的任务，nil 表示彻底删除
+	staged  map[string]*Task
+	history []*Change
+}
+
+func newRedisTx(cmd redis.Cmdable, tx *redis.Tx) *redisTx {
+	return &redisTx{
+		cmd:    cmd,
+		tx:     tx,
+		loaded: make(map[string]*Task),
+		staged: make(map[string]*Task),
+	}
+}
+
+func (t *redisTx) watch(ctx context.Context, keys ...string) error {
+	if t.tx == nil || len(keys) == 0 {
+		return nil
+	}
+	if err := t.tx.Watch(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to watch keys: %w", err)
+	}
+	return nil
+}
+
+// load 返回任务的当前版本（包括已删除的），先查找本事务中的写入
+func (t *redisTx) load(id string) (*Task, error) {
+	if task, ok := t.staged[id]; ok {
+		return task, nil
+	}
+	if task, ok := t.loaded[id]; ok {
+		return task, nil
+	}
+
+	ctx := context.Background()
+	key := redisItemKey(id)
+	if err := t.watch(ctx, key); err != nil {
+		return nil, err
+	}
+	data, err := t.cmd.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		t.loaded[id] = nil
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task: %w", err)
+	}
+	task, err := decodeTask(data)
+	if err != nil {
+		return nil, err
+	}
+	t.loaded[id] = task
+	return task, nil
+}
+
+// put 缓存对任务 id 的写入，task 为 nil 表示彻底删除
+func (t *redisTx) put(id string, task *Task) error {
+	if t.tx == nil {
+		return fmt.Errorf("write outside of transaction")
+	}
+	// 记录原始版本，提交时需要移除旧的索引
+	if _, err := t.load(id); err != nil {
+		return err
+	}
+	t.staged[id] = task
+	return nil
+}
+
+func (t *redisTx) Add(task *Task) error {
+	prepareAdd(task)
+	copied := *task
+	return t.put(copied.ID, &copied)
+}
+
+func (t *redisTx) Get(id string) (*Task, error) {
+	task, err := t.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || task.IsDeleted {
+		return nil, notFound(id)
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (t *redisTx) Update(task *Task) error {
+	existing, err := t.Get(task.ID)
+	if err != nil {
+		return err
+	}
+	return t.put(task.ID, applyUpdate(existing, task))
+}
+
+func (t *redisTx) Delete(id string) error {
+	task, err := t.Get(id)
+	if err != nil {
+		return err
+	}
+	// 标记删除
+	task.IsDeleted = true
+	return t.put(id, task)
+}
+
+func (t *redisTx) GetDeleted(id string) (*Task, error) {
+	task, err := t.load(id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil || !task.IsDeleted {
+		return nil, notFound(id)
+	}
+	copied := *task
+	return &copied, nil
+}
+
+func (t *redisTx) Restore(id string) error {
+	task, err := t.load(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return notFound(id)
+	}
+	if !task.IsDeleted {
+		return fmt.Errorf("%w: %s", ErrNotDeleted, id)
+	}
+	return t.put(id, restored(task))
+}
+
+func (t *redisTx) Purge(id string) error {
+	task, err := t.load(id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return notFound(id)
+	}
+	return t.put(id, nil)
+}
+
+// List 用索引集合的交集缩小范围，再由 matches 确认
+func (t *redisTx) List(params *ListParams) ([]*Task, error) {
+	ctx := context.Background()
+
+	// 已删除的任务不在其他索引中，只能按删除集合查找
+	if params.Deleted {
+		return t.listIDs(ctx, params, redisDeletedKey)
+	}
+	keys := []string{redisIDsKey}
+	if params.Assignee != "" {
+		keys = append(keys, redisIndexKey("assignee", strings.ToLower(params.Assignee)))
+	}
+	if params.Priority != "" {
+		keys = append(keys, redisIndexKey("priority", string(params.Priority)))
+	}
+	if len(params.Status) == 1 {
+		keys = append(keys, redisIndexKey("status", string(params.Status[0])))
+	}
+	for _, tag := range params.Tags {
+		keys = append(keys, redisIndexKey("tag", strings.ToLower(tag)))
+	}
+	if params.SourceMeetingID != "" {
+		keys = append(keys, redisIndexKey("meeting", params.SourceMeetingID))
+	}
+	return t.listIDs(ctx, params, keys...)
+}
+
+// listIDs 读取 keys 交集中的任务
+func (t *redisTx) listIDs(ctx context.Context, params *ListParams, keys ...string) ([]*Task, error) {
+	if err := t.watch(ctx, keys...); err != nil {
+		return nil, err
+	}
+
+	ids, err := t.cmd.SInter(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	// 本事务中写入的任务还没有更新索引，全部交给 matches 判断
+	for id := range t.staged {
+		ids = append(ids, id)
+	}
+
+	var tasks []*Task
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		task, err := t.load(id)
+		if err != nil {
+			return nil, err
+		}
+		if task != nil && matches(task, params) {
+			copied := *task
+			tasks = append(tasks, &copied)
+		}
+	}
+	return sortAndLimit(tasks, params), nil
+}
+
+func (t *redisTx) AppendHistory(change *Change) error {
+	if t.tx == nil {
+		return fmt.Errorf("write outside of transaction")
+	}
+	t.history = append(t.history, change)
+	return nil
+}
+
+func (t *redisTx) History(id string) ([]*Change, error) {
+	items, err := t.cmd.LRange(context.Background(), redisHistoryKey(id), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	changes := make([]*Change, 0, len(items))
+	for _, item := range items {
+		change, err := decodeChange(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	for _, change := range t.history {
+		if change.TaskID == id {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (t *redisTx) Import(tasks []*Task) error {
+	for _, task := range tasks {
+		copied := *task
+		copied.normalize()
+		if err := t.put(copied.ID, &copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *redisTx) Tx(fn func(tx TaskStore) error) error {
+	return fmt.Errorf("nested transaction is not supported")
+}
+
+func (t *redisTx) Close() error {
+	return nil
+}
+
+// commit 在 MULTI 中写入任务、更新索引并追加变更记录
+func (t *redisTx) commit(ctx context.Context) error {
+	if len(t.staged) == 0 && len(t.history) == 0 {
+		return nil
+	}
+	_, err := t.tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for id, task := range t.staged {
+			for _, key := range redisIndexKeys(t.loaded[id]) {
+				pipe.SRem(ctx, key, id)
+			}
+			if task == nil {
+				pipe.Del(ctx, redisItemKey(id))
+				continue
+			}
+			data, err := json.Marshal(task)
+			if err != nil {
+				return fmt.Errorf("failed to marshal task: %w", err)
+			}
+			for _, key := range redisIndexKeys(task) {
+				pipe.SAdd(ctx, key, id)
+			}
+			pipe.Set(ctx, redisItemKey(id), data, 0)
+		}
+		for _, change := range t.history {
+			data, err := json.Marshal(change)
+			if err != nil {
+				return fmt.Errorf("failed to marshal change: %w", err)
+			}
+			pipe.RPush(ctx, redisHistoryKey(change.TaskID), data)
+		}
+		return nil
+	})
+	return err
+}
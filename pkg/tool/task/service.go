@@ -0,0 +1,541 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	resolver "meetingagent/pkg/deadline"
+	"meetingagent/pkg/tool/deadline"
+	"meetingagent/pkg/tool/meeting"
+)
+
+var (
+	// ErrInvalid 请求中的任务不合法，例如缺少标题或枚举值错误
+	ErrInvalid = errors.New("invalid task")
+	// ErrPreconditionFailed If-Match 与任务当前的 ETag 不一致，任务已被其他人修改
+	ErrPreconditionFailed = errors.New("task has been modified")
+)
+
+// Service 任务的增删改查，agent 工具与 /tasks 接口共用
+type Service struct {
+	config *TaskToolConfig
+}
+
+func NewService(ctx context.Context, config *TaskToolConfig) (*Service, error) {
+	var err error
+	if config == nil {
+		config, err = defaultTaskToolConfig(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.Storage == nil {
+		return nil, fmt.Errorf("storage cannot be empty")
+	}
+	if config.MeetingDate == nil {
+		config.MeetingDate = deadline.MeetingDateFromRepository(meeting.NewRepository(nil))
+	}
+	return &Service{config: config}, nil
+}
+
+var (
+	defaultServiceOnce sync.Once
+	defaultService     *Service
+	defaultServiceErr  error
+)
+
+// DefaultService 进程内共享的任务服务，使用 DefaultStore
+func DefaultService() (*Service, error) {
+	defaultServiceOnce.Do(func() {
+		defaultService, defaultServiceErr = NewService(context.Background(), nil)
+	})
+	return defaultService, defaultServiceErr
+}
+
+// ETag 任务内容的摘要，任务的任何字段变化后都会改变，不包括查询时计算的 Rollup
+func ETag(task *Task) string {
+	copied := *task
+	copied.Rollup = nil
+	data, _ := json.Marshal(&copied)
+	sum := sha256.Sum256(data)
+	return `"` + hex.EncodeToString(sum[:8]) + `"`
+}
+
+// etagMatches 检查 If-Match，为空或 * 时不检查，可以是逗号分隔的多个 ETag
+func etagMatches(ifMatch string, task *Task) bool {
+	if ifMatch == "" || ifMatch == "*" {
+		return true
+	}
+	etag := ETag(task)
+	for _, candidate := range strings.Split(ifMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskPatch 部分更新，nil 字段不修改，与 Task 的更新一样空字符串也不修改；
+// ParentID 例外，空字符串表示不再是子任务
+type TaskPatch struct {
+	Title           *string   `json:"title"`
+	Content         *string   `json:"content"`
+	Deadline        *string   `json:"deadline"`
+	Assignee        *string   `json:"assignee"`
+	Priority        *Priority `json:"priority"`
+	Status          *Status   `json:"status"`
+	Completed       *bool     `json:"completed"`
+	Tags            []string  `json:"tags"`
+	SourceMeetingID *string   `json:"source_meeting_id"`
+	SourceTimestamp *string   `json:"source_timestamp"`
+	ParentID        *string   `json:"parent_id"`
+	BlockedBy       []string  `json:"blocked_by"`
+}
+
+// toTask 转换为 Update 使用的 Task
+func (p *TaskPatch) toTask(id string, existing *Task) *Task {
+	deref := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	task := &Task{
+		ID:              id,
+		Title:           deref(p.Title),
+		Content:         deref(p.Content),
+		Deadline:        deref(p.Deadline),
+		Assignee:        deref(p.Assignee),
+		Tags:            p.Tags,
+		SourceMeetingID: deref(p.SourceMeetingID),
+		SourceTimestamp: deref(p.SourceTimestamp),
+		ParentID:        deref(p.ParentID),
+		BlockedBy:       p.BlockedBy,
+	}
+	if p.ParentID != nil && *p.ParentID == "" {
+		task.ParentID = NoParent
+	}
+	if p.Priority != nil {
+		task.Priority = *p.Priority
+	}
+	if p.Status != nil {
+		task.Status = *p.Status
+	}
+	// completed 为 false 表示重新打开已完成的任务，与 status 同时给出时以 status 为准
+	if p.Completed != nil && task.Status == "" {
+		task.Completed = *p.Completed
+		if !*p.Completed && existing.Completed {
+			task.Status = StatusTodo
+		}
+	}
+	return task
+}
+
+// Create 添加任务，ID 由服务生成。所有修改都会在同一事务中记录变更历史，来源见 WithActor
+func (s *Service) Create(ctx context.Context, task *Task) (*Task, error) {
+	if err := validateNew(task); err != nil {
+		return nil, err
+	}
+	task.ID = uuid.New().String()
+	return s.create(ctx, task)
+}
+
+// create 添加已分配 ID 的任务，上级任务与前置任务必须已经存在
+func (s *Service) create(ctx context.Context, task *Task) (*Task, error) {
+	s.resolveDeadline(ctx, task, task.SourceMeetingID)
+	err := s.config.Storage.Tx(func(tx TaskStore) error {
+		if err := checkRelations(tx, nil, task); err != nil {
+			return err
+		}
+		// 存储可能直接保存传入的指针，交给它一份副本
+		added := *task
+		if err := tx.Add(&added); err != nil {
+			return err
+		}
+		*task = added
+		created := added
+		return tx.AppendHistory(newChange(ctx, ChangeCreate, nil, &created))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add task: %w", err)
+	}
+	s.rollup(task)
+	return task, nil
+}
+
+// Get 返回任务，附带子任务与前置任务的汇总
+func (s *Service) Get(id string) (*Task, error) {
+	task, err := s.config.Storage.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	s.rollup(task)
+	return task, nil
+}
+
+// List 返回满足条件的任务，附带子任务与前置任务的汇总
+func (s *Service) List(params *ListParams) ([]*Task, error) {
+	if params == nil {
+		params = &ListParams{}
+	}
+	tasks, err := s.config.Storage.List(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	s.rollup(tasks...)
+	return tasks, nil
+}
+
+// Update 按 patch 中的非空字段更新任务并返回更新后的任务，ifMatch 不为空时任务必须未被修改过
+func (s *Service) Update(ctx context.Context, patch *Task, ifMatch string) (*Task, error) {
+	return s.update(ctx, patch.ID, ifMatch, func(existing *Task) *Task {
+		return patch
+	})
+}
+
+// Patch 与 Update 相同，completed 未给出时不修改状态
+func (s *Service) Patch(ctx context.Context, id string, patch *TaskPatch, ifMatch string) (*Task, error) {
+	return s.update(ctx, id, ifMatch, func(existing *Task) *Task {
+		return patch.toTask(id, existing)
+	})
+}
+
+func (s *Service) update(ctx context.Context, id string, ifMatch string, build func(existing *Task) *Task) (*Task, error) {
+	// 检查、更新与读取放在同一事务中，返回的就是这次更新的结果
+	var updated *Task
+	err := s.config.Storage.Tx(func(tx TaskStore) error {
+		existing, err := tx.Get(id)
+		if err != nil {
+			return err
+		}
+		if !etagMatches(ifMatch, existing) {
+			return ErrPreconditionFailed
+		}
+		patch := build(existing)
+		if err := patch.validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		// 相对截止时间按任务的来源会议换算，请求中没有来源会议时使用已保存的
+		meetingID := patch.SourceMeetingID
+		if meetingID == "" {
+			meetingID = existing.SourceMeetingID
+		}
+		s.resolveDeadline(ctx, patch, meetingID)
+		if err := tx.Update(patch); err != nil {
+			return err
+		}
+		if updated, err = tx.Get(id); err != nil {
+			return err
+		}
+		if err := checkRelations(tx, existing, updated); err != nil {
+			return err
+		}
+		change := newChange(ctx, ChangeUpdate, existing, updated)
+		if len(change.Fields) == 0 {
+			return nil
+		}
+		return tx.AppendHistory(change)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+	s.rollup(updated)
+	return updated, nil
+}
+
+// Delete 标记删除任务，之后可以通过 Restore 恢复，ifMatch 不为空时任务必须未被修改过
+func (s *Service) Delete(ctx context.Context, id string, ifMatch string) error {
+	err := s.config.Storage.Tx(func(tx TaskStore) error {
+		existing, err := tx.Get(id)
+		if err != nil {
+			return err
+		}
+		if !etagMatches(ifMatch, existing) {
+			return ErrPreconditionFailed
+		}
+		if err := tx.Delete(id); err != nil {
+			return err
+		}
+		deleted, err := tx.GetDeleted(id)
+		if err != nil {
+			return err
+		}
+		return tx.AppendHistory(newChange(ctx, ChangeDelete, existing, deleted))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// Restore 恢复已删除的任务并返回恢复后的任务，ifMatch 与删除后的任务比较
+func (s *Service) Restore(ctx context.Context, id string, ifMatch string) (*Task, error) {
+	var task *Task
+	err := s.config.Storage.Tx(func(tx TaskStore) error {
+		existing, err := tx.GetDeleted(id)
+		if errors.Is(err, ErrNotFound) {
+			// 区分任务不存在与任务没有被删除
+			if _, getErr := tx.Get(id); getErr == nil {
+				return fmt.Errorf("%w: %s", ErrNotDeleted, id)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if !etagMatches(ifMatch, existing) {
+			return ErrPreconditionFailed
+		}
+		if err := tx.Restore(id); err != nil {
+			return err
+		}
+		if task, err = tx.Get(id); err != nil {
+			return err
+		}
+		return tx.AppendHistory(newChange(ctx, ChangeRestore, existing, task))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+	s.rollup(task)
+	return task, nil
+}
+
+// Purge 彻底删除任务，已删除与未删除的任务都可以，变更历史保留
+func (s *Service) Purge(ctx context.Context, id string, ifMatch string) error {
+	err := s.config.Storage.Tx(func(tx TaskStore) error {
+		existing, err := tx.Get(id)
+		if errors.Is(err, ErrNotFound) {
+			existing, err = tx.GetDeleted(id)
+		}
+		if err != nil {
+			return err
+		}
+		if !etagMatches(ifMatch, existing) {
+			return ErrPreconditionFailed
+		}
+		if err := tx.Purge(id); err != nil {
+			return err
+		}
+		return tx.AppendHistory(newChange(ctx, ChangePurge, existing, nil))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+	return nil
+}
+
+// History 任务的变更历史，按时间顺序，没有任何记录时返回 ErrNotFound
+func (s *Service) History(id string) ([]*Change, error) {
+	changes, err := s.config.Storage.History(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	if len(changes) == 0 {
+		return nil, notFound(id)
+	}
+	return changes, nil
+}
+
+// checkRelations 检查 task 的上级任务与前置任务存在且不形成环，existing 为修改前的任务，新建时为 nil。
+// 只检查有变化的关系，已删除的任务之后不会再影响其他任务的修改
+func checkRelations(tx TaskStore, existing, task *Task) error {
+	if existing == nil {
+		existing = &Task{}
+	}
+	if task.ParentID != "" && task.ParentID != existing.ParentID {
+		if task.ParentID == task.ID {
+			return fmt.Errorf("%w: a task cannot be its own parent", ErrInvalid)
+		}
+		// 沿上级任务向上查找，遇到自身说明形成了环
+		seen := map[string]bool{task.ID: true}
+		for id := task.ParentID; id != ""; {
+			if seen[id] {
+				return fmt.Errorf("%w: parent %s would create a cycle", ErrInvalid, task.ParentID)
+			}
+			seen[id] = true
+			parent, err := tx.Get(id)
+			if errors.Is(err, ErrNotFound) && id == task.ParentID {
+				return fmt.Errorf("%w: parent task %s not found", ErrInvalid, id)
+			}
+			if errors.Is(err, ErrNotFound) {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			id = parent.ParentID
+		}
+	}
+
+	for _, id := range task.BlockedBy {
+		if slices.Contains(existing.BlockedBy, id) {
+			continue
+		}
+		if id == task.ID {
+			return fmt.Errorf("%w: a task cannot block itself", ErrInvalid)
+		}
+		if _, err := tx.Get(id); errors.Is(err, ErrNotFound) {
+			return fmt.Errorf("%w: blocking task %s not found", ErrInvalid, id)
+		} else if err != nil {
+			return err
+		}
+		// 从新的前置任务出发沿 BlockedBy 查找，能回到自身说明形成了环
+		blocks, err := reaches(tx, id, task.ID)
+		if err != nil {
+			return err
+		}
+		if blocks {
+			return fmt.Errorf("%w: blocked_by %s would create a cycle", ErrInvalid, id)
+		}
+	}
+	return nil
+}
+
+// reaches from 是否经由 BlockedBy 直接或间接依赖 target
+func reaches(tx TaskStore, from, target string) (bool, error) {
+	seen := make(map[string]bool)
+	queue := []string{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		if id == target {
+			return true, nil
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		task, err := tx.Get(id)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		queue = append(queue, task.BlockedBy...)
+	}
+	return false, nil
+}
+
+// rollup 为 tasks 计算全部下级任务的完成情况与未完成的前置任务，失败时只记录日志，任务不带汇总
+func (s *Service) rollup(tasks ...*Task) {
+	if len(tasks) == 0 {
+		return
+	}
+	all, err := s.config.Storage.List(&ListParams{})
+	if err != nil {
+		log.Printf("[task] failed to roll up subtasks: %v", err)
+		return
+	}
+	byID := make(map[string]*Task, len(all))
+	children := make(map[string][]*Task)
+	for _, t := range all {
+		byID[t.ID] = t
+		if t.ParentID != "" {
+			children[t.ParentID] = append(children[t.ParentID], t)
+		}
+	}
+
+	for _, task := range tasks {
+		r := &Rollup{}
+		// 已有数据中可能存在环，seen 防止重复计算
+		seen := map[string]bool{task.ID: true}
+		queue := children[task.ID]
+		for len(queue) > 0 {
+			child := queue[0]
+			queue = queue[1:]
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			queue = append(queue, children[child.ID]...)
+			if child.Status == StatusCancelled {
+				continue
+			}
+			r.Subtasks++
+			if child.Completed {
+				r.Done++
+			}
+		}
+		switch {
+		case r.Subtasks > 0:
+			r.Percent = r.Done * 100 / r.Subtasks
+		case task.Completed:
+			r.Percent = 100
+		}
+		for _, id := range task.BlockedBy {
+			if blocker, ok := byID[id]; ok && !blocker.closed() {
+				r.OpenBlockers = append(r.OpenBlockers, id)
+			}
+		}
+		task.Rollup = r
+	}
+}
+
+// validateNew 检查新任务的必填字段与枚举值
+func validateNew(task *Task) error {
+	if task.Title == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalid)
+	}
+	if err := task.validate(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	return nil
+}
+
+// needsResolve Deadline 不是 RFC3339 时需要换算
+func needsResolve(task *Task) bool {
+	if task.Deadline == "" {
+		return false
+	}
+	_, err := time.Parse(time.RFC3339, task.Deadline)
+	return err != nil
+}
+
+// resolveDeadline 将"下周五"等说法换算为 RFC3339，锚点为来源会议的日期，没有来源会议时为当前时间。
+// 原始说法保存在 DeadlineText，无法换算时 Deadline 保持原样
+func (s *Service) resolveDeadline(ctx context.Context, task *Task, meetingID string) {
+	if !needsResolve(task) {
+		return
+	}
+	if task.DeadlineText == "" {
+		task.DeadlineText = task.Deadline
+	}
+	anchor := deadline.Anchor(ctx, s.config.MeetingDate, meetingID)
+	resolved, err := resolver.Resolve(task.Deadline, anchor)
+	if err != nil {
+		if !errors.Is(err, resolver.ErrUnresolved) {
+			log.Printf("[task] failed to resolve deadline %q: %v", task.Deadline, err)
+		}
+		return
+	}
+	task.Deadline = resolved.RFC3339()
+}
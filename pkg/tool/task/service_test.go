@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestTaskPatchToTask(t *testing.T) {
+	ptr := func(v bool) *bool { return &v }
+	str := func(v string) *string { return &v }
+	status := func(v Status) *Status { return &v }
+	open := &Task{ID: "t1", Status: StatusInProgress}
+	done := &Task{ID: "t1", Status: StatusDone, Completed: true}
+
+	tests := []struct {
+		name      string
+		patch     *TaskPatch
+		existing  *Task
+		status    Status
+		completed bool
+		parentID  string
+	}{
+		{name: "omitted fields stay empty", patch: &TaskPatch{Title: str("新标题")}, existing: done},
+		{name: "completed true", patch: &TaskPatch{Completed: ptr(true)}, existing: open, completed: true},
+		{name: "completed false reopens done task", patch: &TaskPatch{Completed: ptr(false)}, existing: done, status: StatusTodo},
+		{name: "completed false on open task keeps status", patch: &TaskPatch{Completed: ptr(false)}, existing: open},
+		{name: "status wins over completed", patch: &TaskPatch{Status: status(StatusBlocked), Completed: ptr(true)}, existing: open, status: StatusBlocked},
+		{name: "empty parent clears parent", patch: &TaskPatch{ParentID: str("")}, existing: open, parentID: NoParent},
+		{name: "parent", patch: &TaskPatch{ParentID: str("p1")}, existing: open, parentID: "p1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.patch.toTask("t1", tt.existing)
+			if task.ID != "t1" {
+				t.Errorf("ID = %q, want t1", task.ID)
+			}
+			if task.Status != tt.status || task.Completed != tt.completed {
+				t.Errorf("status = %q, completed = %v, want %q, %v", task.Status, task.Completed, tt.status, tt.completed)
+			}
+			if task.ParentID != tt.parentID {
+				t.Errorf("parent = %q, want %q", task.ParentID, tt.parentID)
+			}
+		})
+	}
+}
+
+func TestTaskPatchToTaskApplied(t *testing.T) {
+	// 经 applyUpdate 合并后，未给出 completed 的更新不会重新打开已完成的任务
+	done := &Task{ID: "t1", Title: "写周报", Status: StatusDone, Completed: true}
+	title := "写月报"
+	updated := applyUpdate(done, (&TaskPatch{Title: &title}).toTask("t1", done))
+	if updated.Status != StatusDone || !updated.Completed || updated.Title != title {
+		t.Errorf("updated = %+v, want done task titled %q", updated, title)
+	}
+
+	reopen := false
+	updated = applyUpdate(done, (&TaskPatch{Completed: &reopen}).toTask("t1", done))
+	if updated.Status != StatusTodo || updated.Completed {
+		t.Errorf("updated = %+v, want reopened task", updated)
+	}
+}
+
+// newRelationStore a <- b <- c 为上下级，d 被 c 阻塞，e 是 a 的已取消下级
+func newRelationStore(t *testing.T) TaskStore {
+	t.Helper()
+	store, err := NewJSONLStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range []*Task{
+		{ID: "a", Title: "a"},
+		{ID: "b", Title: "b", ParentID: "a", Status: StatusInProgress},
+		{ID: "c", Title: "c", ParentID: "b", Status: StatusDone},
+		{ID: "d", Title: "d", BlockedBy: []string{"c", "b"}},
+		{ID: "e", Title: "e", ParentID: "a", Status: StatusCancelled},
+	} {
+		if err := store.Add(task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestCheckRelations(t *testing.T) {
+	store := newRelationStore(t)
+	get := func(id string) *Task {
+		task, err := store.Get(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+
+	tests := []struct {
+		name     string
+		existing *Task
+		task     *Task
+		wantErr  bool
+	}{
+		{name: "new task with parent", task: &Task{ID: "f", ParentID: "c"}},
+		{name: "move to another parent", existing: get("d"), task: &Task{ID: "d", ParentID: "a"}},
+		{name: "own parent", existing: get("a"), task: &Task{ID: "a", ParentID: "a"}, wantErr: true},
+		{name: "parent cycle", existing: get("a"), task: &Task{ID: "a", ParentID: "c"}, wantErr: true},
+		{name: "missing parent", existing: get("a"), task: &Task{ID: "a", ParentID: "x"}, wantErr: true},
+		{name: "unchanged parent is not checked", existing: get("b"), task: &Task{ID: "b", ParentID: "a"}},
+		{name: "blocked by open task", existing: get("a"), task: &Task{ID: "a", BlockedBy: []string{"d"}}},
+		{name: "blocked by itself", existing: get("a"), task: &Task{ID: "a", BlockedBy: []string{"a"}}, wantErr: true},
+		{name: "missing blocker", existing: get("a"), task: &Task{ID: "a", BlockedBy: []string{"x"}}, wantErr: true},
+		{name: "blocker cycle", existing: get("c"), task: &Task{ID: "c", BlockedBy: []string{"d"}}, wantErr: true},
+		{name: "existing blockers are kept", existing: get("d"), task: &Task{ID: "d", BlockedBy: []string{"c", "b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRelations(store, tt.existing, tt.task)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("checkRelations() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalid) {
+				t.Errorf("checkRelations() error = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestRollup(t *testing.T) {
+	store := newRelationStore(t)
+	s := &Service{config: &TaskToolConfig{Storage: store}}
+
+	tests := []struct {
+		id       string
+		want     Rollup
+		blockers []string
+	}{
+		// 已取消的 e 不计入，c 是 b 的下级也计入 a
+		{id: "a", want: Rollup{Subtasks: 2, Done: 1, Percent: 50}},
+		{id: "b", want: Rollup{Subtasks: 1, Done: 1, Percent: 100}},
+		{id: "c", want: Rollup{Percent: 100}},
+		{id: "d", want: Rollup{}, blockers: []string{"b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			task, err := store.Get(tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.rollup(task)
+			r := task.Rollup
+			if r == nil {
+				t.Fatal("rollup is nil")
+			}
+			if r.Subtasks != tt.want.Subtasks || r.Done != tt.want.Done || r.Percent != tt.want.Percent {
+				t.Errorf("rollup = %+v, want %+v", *r, tt.want)
+			}
+			if !slices.Equal(r.OpenBlockers, tt.blockers) {
+				t.Errorf("open blockers = %v, want %v", r.OpenBlockers, tt.blockers)
+			}
+		})
+	}
+}
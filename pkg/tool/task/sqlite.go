@@ -0,0 +1,447 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations 按顺序执行的建表语句，已执行到第几条记录在 PRAGMA user_version 中。
+// 任务的完整内容保存在 data 中，其余列用于查询与索引
+var sqliteMigrations = []string{
+	`CREATE TABLE tasks (
+		id                TEXT PRIMARY KEY,
+		data              TEXT NOT NULL,
+		status            TEXT NOT NULL,
+		priority          TEXT NOT NULL,
+		assignee          TEXT NOT NULL DEFAULT '' COLLATE NOCASE,
+		source_meeting_id TEXT NOT NULL DEFAULT '',
+		closed            INTEGER NOT NULL DEFAULT 0,
+		is_deleted        INTEGER NOT NULL DEFAULT 0,
+		created_at        TEXT NOT NULL,
+		updated_at        TEXT NOT NULL
+	);
+	CREATE TABLE task_tags (
+		task_id TEXT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+		tag     TEXT NOT NULL COLLATE NOCASE,
+		PRIMARY KEY (task_id, tag)
+	);
+	CREATE INDEX idx_tasks_status ON tasks(status);
+	CREATE INDEX idx_tasks_assignee ON tasks(assignee);
+	CREATE INDEX idx_tasks_priority ON tasks(priority);
+	CREATE INDEX idx_tasks_meeting ON tasks(source_meeting_id);
+	CREATE INDEX idx_tasks_order ON tasks(is_deleted, closed, created_at DESC);
+	CREATE INDEX idx_task_tags_tag ON task_tags(tag);`,
+	// 变更历史不引用 tasks，彻底删除任务后仍然保留
+	`CREATE TABLE task_history (
+		seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+		task_id    TEXT NOT NULL,
+		data       TEXT NOT NULL,
+		created_at TEXT NOT NULL
+	);
+	CREATE INDEX idx_task_history_task ON task_history(task_id, seq);`,
+}
+
+// SQLiteStore 将任务保存在嵌入式 SQLite 数据库中，适合单机部署
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	// 写事务一开始就加锁（BEGIN IMMEDIATE）。Service 的更新先读后写，默认的 DEFERRED 事务在
+	// 升级为写锁时会直接返回 SQLITE_BUSY，busy_timeout 对这种情况不生效
+	query := url.Values{}
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Set("_txlock", "immediate")
+	db, err := sql.Open("sqlite", "file:"+path+"?"+query.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite: %w", err)
+	}
+
+	s := &SQLiteStore{db: db}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SQLiteStore) migrate() error {
+	var version int
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	for ; version < len(sqliteMigrations); version++ {
+		tx, err := s.db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration: %w", err)
+		}
+		if _, err := tx.Exec(sqliteMigrations[version]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate schema to version %d: %w", version+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to migrate schema to version %d: %w", version+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to migrate schema to version %d: %w", version+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Add(task *Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Add(task)
+	})
+}
+
+func (s *SQLiteStore) Get(id string) (*Task, error) {
+	return (&sqliteTx{q: s.db}).Get(id)
+}
+
+func (s *SQLiteStore) Update(task *Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Update(task)
+	})
+}
+
+func (s *SQLiteStore) Delete(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Delete(id)
+	})
+}
+
+func (s *SQLiteStore) GetDeleted(id string) (*Task, error) {
+	return (&sqliteTx{q: s.db}).GetDeleted(id)
+}
+
+func (s *SQLiteStore) Restore(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Restore(id)
+	})
+}
+
+func (s *SQLiteStore) Purge(id string) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Purge(id)
+	})
+}
+
+func (s *SQLiteStore) List(params *ListParams) ([]*Task, error) {
+	return (&sqliteTx{q: s.db}).List(params)
+}
+
+func (s *SQLiteStore) AppendHistory(change *Change) error {
+	return (&sqliteTx{q: s.db}).AppendHistory(change)
+}
+
+func (s *SQLiteStore) History(id string) ([]*Change, error) {
+	return (&sqliteTx{q: s.db}).History(id)
+}
+
+func (s *SQLiteStore) Import(tasks []*Task) error {
+	return s.Tx(func(tx TaskStore) error {
+		return tx.Import(tasks)
+	})
+}
+
+func (s *SQLiteStore) Tx(fn func(tx TaskStore) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	if err := fn(&sqliteTx{q: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// sqliteQuerier *sql.DB 与 *sql.Tx 的共同方法
+type sqliteQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// sqliteTx 在 q 上执行任务操作，q 为 *sql.Tx 时即为事务
+type sqliteTx struct {
+	q sqliteQuerier
+}
+
+func (t *sqliteTx) Add(task *Task) error {
+	prepareAdd(task)
+	return t.put(task)
+}
+
+func (t *sqliteTx) Get(id string) (*Task, error) {
+	return t.get(id, false)
+}
+
+func (t *sqliteTx) GetDeleted(id string) (*Task, error) {
+	return t.get(id, true)
+}
+
+func (t *sqliteTx) get(id string, deleted bool) (*Task, error) {
+	var data string
+	err := t.q.QueryRowContext(context.Background(),
+		`SELECT data FROM tasks WHERE id = ? AND is_deleted = ?`, id, deleted).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, notFound(id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task: %w", err)
+	}
+	return decodeTask(data)
+}
+
+func (t *sqliteTx) Update(task *Task) error {
+	existing, err := t.Get(task.ID)
+	if err != nil {
+		return err
+	}
+	return t.put(applyUpdate(existing, task))
+}
+
+func (t *sqliteTx) Delete(id string) error {
+	task, err := t.Get(id)
+	if err != nil {
+		return err
+	}
+	// 标记删除
+	task.IsDeleted = true
+	return t.put(task)
+}
+
+func (t *sqliteTx) Restore(id string) error {
+	task, err := t.GetDeleted(id)
+	if errors.Is(err, ErrNotFound) {
+		if _, err := t.Get(id); err == nil {
+			return fmt.Errorf("%w: %s", ErrNotDeleted, id)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return t.put(restored(task))
+}
+
+func (t *sqliteTx) Purge(id string) error {
+	// task_tags 随 tasks 级联删除
+	result, err := t.q.ExecContext(context.Background(), `DELETE FROM tasks WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to purge task: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return notFound(id)
+	}
+	return nil
+}
+
+// List 在 SQL 中按索引列过滤，关键词与大小写等细节再由 matches 确认
+func (t *sqliteTx) List(params *ListParams) ([]*Task, error) {
+	where := []string{"is_deleted = ?"}
+	args := []any{params.Deleted}
+	if params.IsDone != nil {
+		if *params.IsDone {
+			where = append(where, "status = ?")
+		} else {
+			where = append(where, "status <> ?")
+		}
+		args = append(args, StatusDone)
+	}
+	if params.Assignee != "" {
+		where = append(where, "assignee = ?")
+		args = append(args, params.Assignee)
+	}
+	if params.Priority != "" {
+		where = append(where, "priority = ?")
+		args = append(args, params.Priority)
+	}
+	if len(params.Status) > 0 {
+		where = append(where, "status IN (?"+strings.Repeat(", ?", len(params.Status)-1)+")")
+		for _, status := range params.Status {
+			args = append(args, status)
+		}
+	}
+	for _, tag := range params.Tags {
+		where = append(where, "id IN (SELECT task_id FROM task_tags WHERE tag = ?)")
+		args = append(args, tag)
+	}
+	if params.SourceMeetingID != "" {
+		where = append(where, "source_meeting_id = ?")
+		args = append(args, params.SourceMeetingID)
+	}
+
+	query := `SELECT data FROM tasks WHERE ` + strings.Join(where, " AND ") +
+		` ORDER BY closed, created_at DESC, id`
+	// 关键词在 Go 中匹配，此时不能在 SQL 中截断
+	if params.Limit != nil && *params.Limit >= 0 && params.Query == "" {
+		query += " LIMIT ?"
+		args = append(args, *params.Limit)
+	}
+
+	rows, err := t.q.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*Task
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read task: %w", err)
+		}
+		task, err := decodeTask(data)
+		if err != nil {
+			return nil, err
+		}
+		if matches(task, params) {
+			tasks = append(tasks, task)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	return sortAndLimit(tasks, params), nil
+}
+
+func (t *sqliteTx) AppendHistory(change *Change) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change: %w", err)
+	}
+	_, err = t.q.ExecContext(context.Background(),
+		`INSERT INTO task_history (task_id, data, created_at) VALUES (?, ?, ?)`,
+		change.TaskID, string(data), change.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+func (t *sqliteTx) History(id string) ([]*Change, error) {
+	rows, err := t.q.QueryContext(context.Background(),
+		`SELECT data FROM task_history WHERE task_id = ? ORDER BY seq`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*Change
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		change, err := decodeChange(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query history: %w", err)
+	}
+	return changes, nil
+}
+
+func (t *sqliteTx) Import(tasks []*Task) error {
+	for _, task := range tasks {
+		copied := *task
+		copied.normalize()
+		if err := t.put(&copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *sqliteTx) Tx(fn func(tx TaskStore) error) error {
+	return fmt.Errorf("nested transaction is not supported")
+}
+
+func (t *sqliteTx) Close() error {
+	return nil
+}
+
+// put 写入任务及其标签，已存在时覆盖
+func (t *sqliteTx) put(task *Task) error {
+	ctx := context.Background()
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+	_, err = t.q.ExecContext(ctx, `INSERT INTO tasks
+		(id, data, status, priority, assignee, source_meeting_id, closed, is_deleted, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			data = excluded.data, status = excluded.status, priority = excluded.priority,
+			assignee = excluded.assignee, source_meeting_id = excluded.source_meeting_id,
+			closed = excluded.closed, is_deleted = excluded.is_deleted,
+			created_at = excluded.created_at, updated_at = excluded.updated_at`,
+		task.ID, string(data), task.Status, task.Priority, task.Assignee, task.SourceMeetingID,
+		task.closed(), task.IsDeleted, task.CreatedAt, task.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to write task: %w", err)
+	}
+
+	if _, err := t.q.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = ?`, task.ID); err != nil {
+		return fmt.Errorf("failed to write tags: %w", err)
+	}
+	for _, tag := range task.Tags {
+		if _, err := t.q.ExecContext(ctx,
+			`INSERT OR IGNORE INTO task_tags (task_id, tag) VALUES (?, ?)`, task.ID, tag); err != nil {
+			return fmt.Errorf("failed to write tags: %w", err)
+		}
+	}
+	return nil
+}
+
+func decodeTask(data string) (*Task, error) {
+	var task Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+	}
+	return &task, nil
+}
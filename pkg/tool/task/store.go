@@ -0,0 +1,239 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"meetingagent/pkg/env"
+)
+
+// ErrNotFound 任务不存在或已删除
+var ErrNotFound = errors.New("task not found")
+
+// TaskStore 任务的存储，实现有 JSONL 文件、SQLite 与 Redis，由 TASK_STORE 选择
+type TaskStore interface {
+	// Add 添加任务，补齐创建时间与默认值
+	Add(task *Task) error
+	// Get 返回任务的副本，已删除的任务返回 ErrNotFound
+	Get(id string) (*Task, error)
+	// Update 只更新 task 中的非空字段，读取与写入是原子的
+	Update(task *Task) error
+	// Delete 标记删除
+	Delete(id string) error
+	// GetDeleted 返回已删除任务的副本，未删除的任务返回 ErrNotFound
+	GetDeleted(id string) (*Task, error)
+	// Restore 撤销删除，任务没有被删除时返回 ErrNotDeleted
+	Restore(id string) error
+	// Purge 彻底删除任务，已删除与未删除的任务都可以，变更历史保留
+	Purge(id string) error
+	// List 返回满足条件的任务，params.Deleted 为 true 时只在已删除的任务中查找
+	List(params *ListParams) ([]*Task, error)
+	// AppendHistory 追加一条变更记录
+	AppendHistory(change *Change) error
+	// History 按时间顺序返回任务的变更记录，任务被彻底删除后仍然可以查询
+	History(id string) ([]*Change, error)
+	// Tx 在一个事务中执行 fn，fn 返回错误时其中的修改都不生效。
+	// fn 中只能使用传入的 tx，不支持嵌套事务
+	Tx(fn func(tx TaskStore) error) error
+	// Import 原样写入任务，保留 ID、时间与删除标记，已存在的任务会被覆盖，用于迁移
+	Import(tasks []*Task) error
+	Close() error
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultStore TaskStore
+	defaultErr   error
+)
+
+// DefaultStore 进程内共享的任务存储，配置见 NewStoreFromEnv
+func DefaultStore() (TaskStore, error) {
+	defaultOnce.Do(func() {
+		defaultStore, defaultErr = NewStoreFromEnv()
+	})
+	return defaultStore, defaultErr
+}
+
+// NewStoreFromEnv 按环境变量创建任务存储：
+//   - TASK_STORE: jsonl（默认）、sqlite 或 redis
+//   - TASK_DIR: jsonl 文件所在目录，默认 ./data/task
+//   - TASK_SQLITE_PATH: SQLite 数据库文件，默认 ./data/task/tasks.db
+//   - REDIS_ADDR: redis 地址，默认 localhost:6379
+func NewStoreFromEnv() (TaskStore, error) {
+	return NewStore(env.GetString("TASK_STORE", "jsonl"))
+}
+
+// NewStore 创建 kind 指定的任务存储，其余配置来自环境变量
+func NewStore(kind string) (TaskStore, error) {
+	dir := env.GetString("TASK_DIR", "./data/task")
+	switch kind {
+	case "jsonl":
+		return NewJSONLStore(dir)
+	case "sqlite":
+		return NewSQLiteStore(env.GetString("TASK_SQLITE_PATH", filepath.Join(dir, "tasks.db")))
+	case "redis":
+		return NewRedisStore(env.GetString("REDIS_ADDR", "localhost:6379"))
+	default:
+		return nil, fmt.Errorf("unknown task store: %s", kind)
+	}
+}
+
+func notFound(id string) error {
+	return fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// restored 撤销删除后的任务
+func restored(task *Task) *Task {
+	copied := *task
+	copied.IsDeleted = false
+	copied.UpdatedAt = time.Now().Format(time.RFC3339)
+	return &copied
+}
+
+// prepareAdd 补齐新任务的字段，各实现的 Add 共用
+func prepareAdd(task *Task) {
+	task.CreatedAt = time.Now().Format(time.RFC3339)
+	task.UpdatedAt = task.CreatedAt
+	task.IsDeleted = false
+	task.normalize()
+}
+
+// applyUpdate 将 patch 中的非空字段合并到 existing 的副本上
+func applyUpdate(existing *Task, patch *Task) *Task {
+	updated := *existing
+	if patch.Title != "" {
+		updated.Title = patch.Title
+	}
+	if patch.Content != "" {
+		updated.Content = patch.Content
+	}
+	// 修改截止时间时原始说法一并替换
+	if patch.Deadline != "" {
+		updated.Deadline = patch.Deadline
+		updated.DeadlineText = patch.DeadlineText
+	}
+	if patch.Assignee != "" {
+		updated.Assignee = patch.Assignee
+	}
+	if patch.Priority != "" {
+		updated.Priority = patch.Priority
+	}
+	// Tags 为 nil 时不修改，空数组表示清空
+	if patch.Tags != nil {
+		updated.Tags = patch.Tags
+	}
+	if patch.SourceMeetingID != "" {
+		updated.SourceMeetingID = patch.SourceMeetingID
+	}
+	if patch.SourceTimestamp != "" {
+		updated.SourceTimestamp = patch.SourceTimestamp
+	}
+	switch patch.ParentID {
+	case "":
+	case NoParent:
+		updated.ParentID = ""
+	default:
+		updated.ParentID = patch.ParentID
+	}
+	// BlockedBy 与 Tags 相同，空数组表示清空
+	if patch.BlockedBy != nil {
+		updated.BlockedBy = patch.BlockedBy
+	}
+	// 同时给出时以 Status 为准。Completed 是布尔值，无法区分未给出与 false，
+	// 只有为 true 时才标记完成，重新打开任务需要给出 Status
+	if patch.Status != "" {
+		updated.Status = patch.Status
+	} else if patch.Completed {
+		updated.Status = StatusDone
+	}
+	updated.Completed = updated.Status == StatusDone
+	updated.UpdatedAt = time.Now().Format(time.RFC3339)
+	return &updated
+}
+
+// matches 检查任务是否满足 params 中的全部条件，已删除的任务只在 params.Deleted 时匹配
+func matches(task *Task, params *ListParams) bool {
+	if task.IsDeleted != params.Deleted {
+		return false
+	}
+	if params.Query != "" && !contains(task.Title, params.Query) && !contains(task.Content, params.Query) {
+		return false
+	}
+	if params.IsDone != nil && task.Completed != *params.IsDone {
+		return false
+	}
+	return matchFilters(task, params)
+}
+
+// matchFilters 检查负责人、优先级、状态、标签、来源会议与上级任务，空条件不过滤
+func matchFilters(task *Task, params *ListParams) bool {
+	if params.Assignee != "" && !strings.EqualFold(task.Assignee, params.Assignee) {
+		return false
+	}
+	if params.Priority != "" && task.Priority != params.Priority {
+		return false
+	}
+	if len(params.Status) > 0 && !slices.Contains(params.Status, task.Status) {
+		return false
+	}
+	for _, tag := range params.Tags {
+		if !slices.ContainsFunc(task.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			return false
+		}
+	}
+	if params.SourceMeetingID != "" && task.SourceMeetingID != params.SourceMeetingID {
+		return false
+	}
+	if params.ParentID != "" && task.ParentID != params.ParentID {
+		return false
+	}
+	return true
+}
+
+// closed 已完成与已取消的任务排在列表后面
+func (t *Task) closed() bool {
+	return t.Completed || t.Status == StatusCancelled
+}
+
+// sortAndLimit 未完成的在前，已完成与已取消的在后，各自按创建时间排序（最新的在前面）
+func sortAndLimit(tasks []*Task, params *ListParams) []*Task {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].closed() != tasks[j].closed() {
+			return !tasks[i].closed()
+		}
+		if tasks[i].CreatedAt != tasks[j].CreatedAt {
+			return tasks[i].CreatedAt > tasks[j].CreatedAt
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	if params.Limit != nil && *params.Limit >= 0 && len(tasks) > *params.Limit {
+		tasks = tasks[:*params.Limit]
+	}
+	return tasks
+}
+
+func contains(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
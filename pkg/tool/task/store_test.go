@@ -0,0 +1,169 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package task
+
+import (
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestApplyUpdate(t *testing.T) {
+	existing := func() *Task {
+		return &Task{
+			ID:       "t1",
+			Title:    "写周报",
+			Assignee: "alice",
+			Priority: PriorityMedium,
+			Status:   StatusTodo,
+			Tags:     []string{"report"},
+			ParentID: "p1",
+		}
+	}
+	done := existing()
+	done.Status, done.Completed = StatusDone, true
+
+	tests := []struct {
+		name      string
+		existing  *Task
+		patch     *Task
+		check     func(*Task) bool
+		status    Status
+		completed bool
+	}{
+		{
+			name:     "empty patch keeps fields",
+			existing: existing(),
+			patch:    &Task{},
+			check: func(u *Task) bool {
+				return u.Title == "写周报" && u.Assignee == "alice" && u.ParentID == "p1" && slices.Equal(u.Tags, []string{"report"})
+			},
+			status: StatusTodo,
+		},
+		{
+			name:     "non-empty fields overwrite",
+			existing: existing(),
+			patch:    &Task{Title: "写月报", Priority: PriorityHigh},
+			check: func(u *Task) bool {
+				return u.Title == "写月报" && u.Priority == PriorityHigh && u.Assignee == "alice"
+			},
+			status: StatusTodo,
+		},
+		{
+			name:     "empty tags clear",
+			existing: existing(),
+			patch:    &Task{Tags: []string{}},
+			check:    func(u *Task) bool { return u.Tags != nil && len(u.Tags) == 0 },
+			status:   StatusTodo,
+		},
+		{
+			name:     "no parent clears parent",
+			existing: existing(),
+			patch:    &Task{ParentID: NoParent},
+			check:    func(u *Task) bool { return u.ParentID == "" },
+			status:   StatusTodo,
+		},
+		{
+			name:      "completed marks done",
+			existing:  existing(),
+			patch:     &Task{Completed: true},
+			status:    StatusDone,
+			completed: true,
+		},
+		{
+			name:      "omitted completed keeps done task done",
+			existing:  done,
+			patch:     &Task{Title: "写周报 v2"},
+			status:    StatusDone,
+			completed: true,
+		},
+		{
+			name:     "status reopens done task",
+			existing: done,
+			patch:    &Task{Status: StatusInProgress},
+			status:   StatusInProgress,
+		},
+		{
+			name:     "status wins over completed",
+			existing: existing(),
+			patch:    &Task{Status: StatusBlocked, Completed: true},
+			status:   StatusBlocked,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := *tt.existing
+			updated := applyUpdate(tt.existing, tt.patch)
+			if updated.Status != tt.status || updated.Completed != tt.completed {
+				t.Errorf("status = %s, completed = %v, want %s, %v", updated.Status, updated.Completed, tt.status, tt.completed)
+			}
+			if tt.check != nil && !tt.check(updated) {
+				t.Errorf("unexpected result: %+v", updated)
+			}
+			if tt.existing.Title != before.Title || tt.existing.Status != before.Status {
+				t.Errorf("existing task was modified: %+v", tt.existing)
+			}
+		})
+	}
+}
+
+func TestSQLiteStoreConcurrentUpdate(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	if err := store.Add(&Task{ID: "t1", Title: "0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 与 Service.update 相同，先读后写；并发的事务应依次执行，而不是在升级为写锁时失败
+	const workers = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.Tx(func(tx TaskStore) error {
+				task, err := tx.Get("t1")
+				if err != nil {
+					return err
+				}
+				n, _ := strconv.Atoi(task.Title)
+				return tx.Update(&Task{ID: "t1", Title: strconv.Itoa(n + 1)})
+			})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent update failed: %v", err)
+		}
+	}
+
+	task, err := store.Get("t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.Title != strconv.Itoa(workers) {
+		t.Errorf("title = %s, want %d, updates were lost", task.Title, workers)
+	}
+}
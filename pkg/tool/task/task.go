@@ -19,10 +19,13 @@ package task
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/components/tool/utils"
 	"github.com/google/uuid"
+
+	"meetingagent/pkg/tool/deadline"
 )
 
 type Action string
@@ -33,85 +36,224 @@ const (
 	ActionUpdate Action = "update"
 	ActionDelete Action = "delete"
 	ActionList   Action = "list"
+	// ActionRestore 恢复已删除的任务
+	ActionRestore Action = "restore"
+	// ActionHistory 查看任务的变更历史
+	ActionHistory Action = "history"
+)
+
+// Status 任务状态，done 与 Completed 为 true 等价
+type Status string
+
+const (
+	StatusTodo       Status = "todo"
+	StatusInProgress Status = "in_progress"
+	StatusBlocked    Status = "blocked"
+	StatusDone       Status = "done"
+	StatusCancelled  Status = "cancelled"
 )
 
+func (s Status) Valid() bool {
+	switch s {
+	case StatusTodo, StatusInProgress, StatusBlocked, StatusDone, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Priority 任务优先级
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityMedium Priority = "medium"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+func (p Priority) Valid() bool {
+	switch p {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
 type Task struct {
 	ID        string `json:"id" jsonschema:"description=id of the task"`
 	Title     string `json:"title" jsonschema:"description=title of the task"`
 	Content   string `json:"content" jsonschema:"description=content of the task"`
-	Completed bool   `json:"completed" jsonschema:"description=completed status of the task"`
-	Deadline  string `json:"deadline" jsonschema:"description=deadline of the task"`
+	Completed bool   `json:"completed" jsonschema:"description=completed status of the task, same as status done. on update only true takes effect, set status to reopen a task"`
+	Deadline  string `json:"deadline" jsonschema:"description=deadline of the task, RFC3339 or as said in the meeting (e.g. 下周五, end of sprint), relative deadlines are converted counting from the source meeting date"`
+	// DeadlineText 换算前的原始说法，Deadline 是换算后的 RFC3339 时间，无法换算时两者相同
+	DeadlineText string   `json:"deadline_text,omitempty" jsonschema:"description=deadline as originally said, filled in automatically"`
+	Assignee     string   `json:"assignee,omitempty" jsonschema:"description=person responsible for the task"`
+	Priority     Priority `json:"priority,omitempty" jsonschema:"description=priority of the task, default medium,enum=low,enum=medium,enum=high,enum=urgent"`
+	Status       Status   `json:"status,omitempty" jsonschema:"description=status of the task, default todo,enum=todo,enum=in_progress,enum=blocked,enum=done,enum=cancelled"`
+	Tags         []string `json:"tags,omitempty" jsonschema:"description=tags of the task, replaces the existing tags on update"`
+	// SourceMeetingID 与 SourceTimestamp 记录任务来自哪场会议的哪个时间点
+	SourceMeetingID string `json:"source_meeting_id,omitempty" jsonschema:"description=id of the meeting the task comes from"`
+	SourceTimestamp string `json:"source_timestamp,omitempty" jsonschema:"description=time in the meeting transcript where the task was raised, e.g. 00:12:30"`
+	// ParentID 与 BlockedBy 是任务之间的关系，不允许出现环
+	ParentID  string   `json:"parent_id,omitempty" jsonschema:"description=id or ref of the parent task, the task is a subtask of it. none removes the parent on update"`
+	BlockedBy []string `json:"blocked_by,omitempty" jsonschema:"description=ids or refs of tasks that must be finished first, replaces the existing ones on update"`
+	// Ref 同一次 add 中其他任务的 parent_id 与 blocked_by 可以引用它，不保存
+	Ref       string `json:"ref,omitempty" jsonschema:"description=temporary name of the task within one add call, other tasks in the same call can use it in parent_id and blocked_by before the ids exist"`
 	IsDeleted bool   `json:"is_deleted" jsonschema:"-"`
 
 	CreatedAt string `json:"created_at" jsonschema:"description=created time of the task"`
+	UpdatedAt string `json:"updated_at,omitempty" jsonschema:"description=last updated time of the task"`
+
+	// Rollup 查询时计算，不保存
+	Rollup *Rollup `json:"rollup,omitempty" jsonschema:"-"`
+}
+
+// NoParent 更新时将 ParentID 设为它表示不再是子任务
+const NoParent = "none"
+
+// Rollup 子任务完成情况与未完成的前置任务
+type Rollup struct {
+	// Subtasks 全部下级任务的数量，不包括已取消的
+	Subtasks int `json:"subtasks"`
+	// Done 其中已完成的数量
+	Done int `json:"done"`
+	// Percent 完成百分比，没有下级任务时按任务自身的状态为 0 或 100
+	Percent int `json:"percent"`
+	// OpenBlockers 尚未完成的前置任务
+	OpenBlockers []string `json:"open_blockers,omitempty"`
+}
+
+// normalize 补齐默认值，并让 Status 与 Completed 保持一致，旧数据只有 Completed
+func (t *Task) normalize() {
+	if t.Priority == "" {
+		t.Priority = PriorityMedium
+	}
+	switch {
+	case t.Status == "" && t.Completed:
+		t.Status = StatusDone
+	case t.Status == "":
+		t.Status = StatusTodo
+	}
+	t.Completed = t.Status == StatusDone
+	if t.UpdatedAt == "" {
+		t.UpdatedAt = t.CreatedAt
+	}
+	t.Ref = ""
+	t.Rollup = nil
+}
+
+// validate 检查枚举字段，空值表示使用默认值或不修改
+func (t *Task) validate() error {
+	if t.Status != "" && !t.Status.Valid() {
+		return fmt.Errorf("invalid status: %s", t.Status)
+	}
+	if t.Priority != "" && !t.Priority.Valid() {
+		return fmt.Errorf("invalid priority: %s", t.Priority)
+	}
+	return nil
 }
 
 type TaskRequest struct {
-	Action Action      `json:"action" jsonschema:"description=action to perform, enum:add,update,delete,list"`
-	Task   *Task       `json:"task" jsonschema:"description=task to add, update, or delete"`
-	List   *ListParams `json:"list" jsonschema:"description=list parameters"`
+	Action Action `json:"action" jsonschema:"description=action to perform,enum=add,enum=get,enum=update,enum=delete,enum=list,enum=restore,enum=history"`
+	Task   *Task  `json:"task" jsonschema:"description=task to add, get, update, delete, restore, or show the history of"`
+	// Tasks 批量操作，与 Task 同时给出时一并处理
+	Tasks []*Task     `json:"tasks,omitempty" jsonschema:"description=several tasks to add, get, update, or delete in one call, e.g. all action items of a meeting"`
+	List  *ListParams `json:"list" jsonschema:"description=list parameters"`
+	// AllowDuplicates add 时不查重，用于确认相似任务不是重复后再次添加
+	AllowDuplicates bool `json:"allow_duplicates,omitempty" jsonschema:"description=for add, add the tasks even if similar open tasks exist, use it only after confirming the returned duplicates are different tasks"`
 }
 
 type ListParams struct {
-	Query  string `json:"query" jsonschema:"description=query to search"`
-	IsDone *bool  `json:"is_done" jsonschema:"description=filter by completed status"`
-	Limit  *int   `json:"limit" jsonschema:"description=limit the number of results"`
+	Query           string   `json:"query" jsonschema:"description=query to search"`
+	IsDone          *bool    `json:"is_done" jsonschema:"description=filter by completed status"`
+	Assignee        string   `json:"assignee,omitempty" jsonschema:"description=filter by assignee"`
+	Priority        Priority `json:"priority,omitempty" jsonschema:"description=filter by priority,enum=low,enum=medium,enum=high,enum=urgent"`
+	Status          []Status `json:"status,omitempty" jsonschema:"description=filter by any of the statuses"`
+	Tags            []string `json:"tags,omitempty" jsonschema:"description=filter by tags, tasks must have all of them"`
+	SourceMeetingID string   `json:"source_meeting_id,omitempty" jsonschema:"description=filter by the meeting the task comes from"`
+	ParentID        string   `json:"parent_id,omitempty" jsonschema:"description=filter by parent task, i.e. list its direct subtasks"`
+	Limit           *int     `json:"limit" jsonschema:"description=limit the number of results"`
+	// Deleted 只列出已删除的任务，用于恢复
+	Deleted bool `json:"deleted,omitempty" jsonschema:"description=list deleted tasks instead, e.g. to find a task to restore"`
 }
 
+// 响应的 status
+const (
+	ResponseSuccess = "success"
+	// ResponsePartial 批量操作中部分任务失败，失败原因见 Results
+	ResponsePartial = "partial"
+	ResponseError   = "error"
+)
+
 type TaskResponse struct {
-	Status string `json:"status" jsonschema:"description=status of the response"`
+	Status string `json:"status" jsonschema:"description=status of the response, success, partial or error"`
 
 	TaskList []*Task `json:"task_list" jsonschema:"description=list of tasks"`
 
+	// Results 批量操作中每个任务的结果，顺序与请求一致
+	Results []*TaskResult `json:"results,omitempty" jsonschema:"description=result of each task in a batch operation"`
+
+	History []*Change `json:"history,omitempty" jsonschema:"description=changes of the task for the history action, oldest first"`
+
+	// Duplicates add 时与已有任务相似的新任务，以及对它们的处理
+	Duplicates []*Duplicate `json:"duplicates,omitempty" jsonschema:"description=new tasks of an add that look like existing open tasks, and whether they were merged into them, skipped, or not added (candidate) pending confirmation"`
+
 	Error string `json:"error" jsonschema:"description=error message"`
 }
 
+type TaskResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// TaskToolImpl agent 使用的 task_manager 工具，是 Service 的一层转换
 type TaskToolImpl struct {
-	config *TaskToolConfig
+	service *Service
 }
 
 type TaskToolConfig struct {
-	Storage *Storage
+	Storage TaskStore
+	// MeetingDate 换算相对截止时间时查询来源会议的日期，默认读取 POST /meeting 保存的会议
+	MeetingDate deadline.MeetingDateFunc
+	// Duplicates add 时的查重配置，nil 表示不查重
+	Duplicates *DuplicateConfig
 }
 
 func defaultTaskToolConfig(ctx context.Context) (*TaskToolConfig, error) {
+	store, err := DefaultStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to init task store: %w", err)
+	}
+	duplicates, err := duplicateConfigFromEnv(ctx)
+	if err != nil {
+		return nil, err
+	}
 	config := &TaskToolConfig{
-		Storage: GetDefaultStorage(),
+		Storage:    store,
+		Duplicates: duplicates,
 	}
 	return config, nil
 }
 
 func NewTaskToolImpl(ctx context.Context, config *TaskToolConfig) (*TaskToolImpl, error) {
-	var err error
-	if config == nil {
-		config, err = defaultTaskToolConfig(ctx)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	if config.Storage == nil {
-		return nil, fmt.Errorf("storage cannot be empty")
+	service, err := NewService(ctx, config)
+	if err != nil {
+		return nil, err
 	}
+	return NewTaskToolFromService(service), nil
+}
 
-	t := &TaskToolImpl{config: config}
-
-	return t, nil
+// NewTaskToolFromService 使用已有的 Service，与 /tasks 接口共用同一份配置
+func NewTaskToolFromService(service *Service) *TaskToolImpl {
+	return &TaskToolImpl{service: service}
 }
 
 func NewTaskTool(ctx context.Context, config *TaskToolConfig) (tn tool.BaseTool, err error) {
-	if config == nil {
-		config, err = defaultTaskToolConfig(ctx)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	if config.Storage == nil {
-		return nil, fmt.Errorf("storage cannot be empty")
+	t, err := NewTaskToolImpl(ctx, config)
+	if err != nil {
+		return nil, err
 	}
-
-	t := &TaskToolImpl{config: config}
 	tn, err = t.ToEinoTool()
 	if err != nil {
 		return nil, err
@@ -120,79 +262,285 @@ func NewTaskTool(ctx context.Context, config *TaskToolConfig) (tn tool.BaseTool,
 }
 
 func (t *TaskToolImpl) ToEinoTool() (tool.BaseTool, error) {
-	return utils.InferTool("task_manager", "task manager tool, you can add, get, update, delete, list tasks", t.Invoke)
+	return utils.InferTool("task_manager", "task manager tool, you can add, get, update, delete, list tasks, restore deleted tasks and show the change history of a task. add, get, update, delete and restore accept several tasks at once in tasks, use it to register all action items of a meeting in one call. tasks can have subtasks (parent_id) and depend on other tasks (blocked_by), within one add call refer to the other new tasks by their ref. add checks for similar open tasks first, see duplicates in the response", t.Invoke)
 }
 
+// Invoke 执行一次任务操作。请求有误时 status 为 error 且不修改任何任务；
+// 批量操作中部分任务失败时 status 为 partial，每个任务的结果见 Results
 func (t *TaskToolImpl) Invoke(ctx context.Context, req *TaskRequest) (res *TaskResponse, err error) {
-	res = &TaskResponse{}
+	res = &TaskResponse{Status: ResponseSuccess}
 
 	switch req.Action {
 	case ActionAdd:
-		if req.Task == nil {
-			res.Status = "error"
-			res.Error = "task is required for add action"
-			return res, nil
+		err = t.add(ctx, req, res)
+	case ActionGet:
+		err = t.get(req, res)
+	case ActionUpdate:
+		err = t.update(ctx, req, res)
+	case ActionDelete:
+		err = t.delete(ctx, req, res)
+	case ActionRestore:
+		err = t.restore(ctx, req, res)
+	case ActionHistory:
+		err = t.history(req, res)
+	case ActionList:
+		res.TaskList, err = t.service.List(req.List)
+	default:
+		err = fmt.Errorf("unknown action: %s", req.Action)
+	}
+
+	if err != nil {
+		res.Status = ResponseError
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+// items 返回请求中的全部任务
+func (req *TaskRequest) items() []*Task {
+	items := make([]*Task, 0, len(req.Tasks)+1)
+	if req.Task != nil {
+		items = append(items, req.Task)
+	}
+	for _, task := range req.Tasks {
+		if task != nil {
+			items = append(items, task)
 		}
-		if req.Task.Title == "" {
-			res.Status = "error"
-			res.Error = "title is required"
-			return res, nil
+	}
+	return items
+}
+
+// requireIDs 检查每个任务都有 ID
+func requireIDs(items []*Task, action Action) error {
+	if len(items) == 0 {
+		return fmt.Errorf("task is required for %s action", action)
+	}
+	for i, task := range items {
+		if task.ID == "" {
+			return fmt.Errorf("task %d: id is required", i)
 		}
-		req.Task.ID = uuid.New().String()
-		if err := t.config.Storage.Add(req.Task); err != nil {
-			res.Status = "error"
-			res.Error = fmt.Sprintf("failed to add task: %v", err)
-			return res, nil
+	}
+	return nil
+}
+
+func (t *TaskToolImpl) add(ctx context.Context, req *TaskRequest, res *TaskResponse) error {
+	items := req.items()
+	if len(items) == 0 {
+		return fmt.Errorf("task is required for add action")
+	}
+	// 先检查全部任务，有一个不合法时都不添加
+	for i, task := range items {
+		if err := validateNew(task); err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
 		}
-		res.TaskList = []*Task{req.Task}
+	}
+	ordered, err := planOrder(items)
+	if err != nil {
+		return err
+	}
 
-	case ActionUpdate:
-		if req.Task == nil {
-			res.Status = "error"
-			res.Error = "task is required for update action"
-			return res, nil
+	// replaced 合并或沿用了已有任务的新任务 ID 对应的已有任务 ID，同一请求中引用它的任务改为引用已有任务；
+	// notAdded 因疑似重复而未添加的新任务，引用它的任务也不添加
+	replaced := make(map[string]string)
+	notAdded := make(map[string]bool)
+	if err := t.each(ordered, res, func(task *Task) (*Task, error) {
+		for _, ref := range append([]string{task.ParentID}, task.BlockedBy...) {
+			if notAdded[ref] {
+				notAdded[task.ID] = true
+				return nil, fmt.Errorf("%w: depends on task %s, which was not added as a likely duplicate", ErrInvalid, ref)
+			}
 		}
-		if req.Task.ID == "" {
-			res.Status = "error"
-			res.Error = "id is required"
-			return res, nil
+		if id, ok := replaced[task.ParentID]; ok {
+			task.ParentID = id
 		}
-		if err := t.config.Storage.Update(req.Task); err != nil {
-			res.Status = "error"
-			res.Error = fmt.Sprintf("failed to update task: %v", err)
-			return res, nil
+		for i, blocker := range task.BlockedBy {
+			if id, ok := replaced[blocker]; ok {
+				task.BlockedBy[i] = id
+			}
 		}
-		res.TaskList = []*Task{req.Task}
+		if req.AllowDuplicates {
+			return t.service.create(ctx, task)
+		}
+		created, dup, err := t.service.createUnique(ctx, task)
+		if dup == nil {
+			return created, err
+		}
+		res.Duplicates = append(res.Duplicates, dup)
+		if dup.Action == DuplicateActionCandidate || err != nil {
+			notAdded[task.ID] = true
+			return created, err
+		}
+		replaced[task.ID] = created.ID
+		task.ID = created.ID
+		return created, err
+	}); err != nil {
+		return err
+	}
+	// 按依赖顺序创建，结果恢复为请求中的顺序
+	position := make(map[string]int, len(items))
+	for i, task := range items {
+		position[task.ID] = i
+	}
+	sort.SliceStable(res.TaskList, func(i, j int) bool {
+		return position[res.TaskList[i].ID] < position[res.TaskList[j].ID]
+	})
+	sort.SliceStable(res.Results, func(i, j int) bool {
+		return position[res.Results[i].ID] < position[res.Results[j].ID]
+	})
+	return nil
+}
 
-	case ActionDelete:
-		if req.Task == nil || req.Task.ID == "" {
-			res.Status = "error"
-			res.Error = "task id is required for delete action"
-			return res, nil
+// planOrder 为新任务分配 ID，将 parent_id 与 blocked_by 中引用的 ref 换成 ID，
+// 并排序为被引用的任务在前，同一请求中的引用形成环时返回错误
+func planOrder(items []*Task) ([]*Task, error) {
+	refs := make(map[string]*Task)
+	for i, task := range items {
+		task.ID = uuid.New().String()
+		if task.Ref == "" {
+			continue
+		}
+		if _, ok := refs[task.Ref]; ok {
+			return nil, fmt.Errorf("task %d: duplicate ref %s", i, task.Ref)
 		}
-		if err := t.config.Storage.Delete(req.Task.ID); err != nil {
-			res.Status = "error"
-			res.Error = fmt.Sprintf("failed to delete task: %v", err)
-			return res, nil
+		refs[task.Ref] = task
+	}
+
+	// deps 每个任务依赖的同一请求中的任务
+	deps := make(map[*Task][]*Task)
+	resolve := func(task *Task, ref string) string {
+		if target, ok := refs[ref]; ok {
+			deps[task] = append(deps[task], target)
+			return target.ID
+		}
+		return ref
+	}
+	for _, task := range items {
+		task.ParentID = resolve(task, task.ParentID)
+		for i, ref := range task.BlockedBy {
+			task.BlockedBy[i] = resolve(task, ref)
 		}
+	}
 
-	case ActionList:
-		if req.List == nil {
-			req.List = &ListParams{}
+	ordered := make([]*Task, 0, len(items))
+	// state 0 未访问，1 访问中，2 已加入
+	state := make(map[*Task]int)
+	var visit func(task *Task) error
+	visit = func(task *Task) error {
+		switch state[task] {
+		case 1:
+			return fmt.Errorf("%w: ref %s is part of a cycle", ErrInvalid, task.Ref)
+		case 2:
+			return nil
 		}
-		tasks, err := t.config.Storage.List(req.List)
+		state[task] = 1
+		for _, dep := range deps[task] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[task] = 2
+		ordered = append(ordered, task)
+		return nil
+	}
+	for _, task := range items {
+		if err := visit(task); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+func (t *TaskToolImpl) get(req *TaskRequest, res *TaskResponse) error {
+	items := req.items()
+	if err := requireIDs(items, ActionGet); err != nil {
+		return err
+	}
+	return t.each(items, res, func(task *Task) (*Task, error) {
+		return t.service.Get(task.ID)
+	})
+}
+
+func (t *TaskToolImpl) update(ctx context.Context, req *TaskRequest, res *TaskResponse) error {
+	items := req.items()
+	if err := requireIDs(items, ActionUpdate); err != nil {
+		return err
+	}
+	for i, task := range items {
+		if err := task.validate(); err != nil {
+			return fmt.Errorf("task %d: %w", i, err)
+		}
+	}
+	return t.each(items, res, func(task *Task) (*Task, error) {
+		return t.service.Update(ctx, task, "")
+	})
+}
+
+func (t *TaskToolImpl) delete(ctx context.Context, req *TaskRequest, res *TaskResponse) error {
+	items := req.items()
+	if err := requireIDs(items, ActionDelete); err != nil {
+		return err
+	}
+	return t.each(items, res, func(task *Task) (*Task, error) {
+		return nil, t.service.Delete(ctx, task.ID, "")
+	})
+}
+
+func (t *TaskToolImpl) restore(ctx context.Context, req *TaskRequest, res *TaskResponse) error {
+	items := req.items()
+	if err := requireIDs(items, ActionRestore); err != nil {
+		return err
+	}
+	return t.each(items, res, func(task *Task) (*Task, error) {
+		return t.service.Restore(ctx, task.ID, "")
+	})
+}
+
+func (t *TaskToolImpl) history(req *TaskRequest, res *TaskResponse) error {
+	if req.Task == nil || req.Task.ID == "" {
+		return fmt.Errorf("task id is required for history action")
+	}
+	changes, err := t.service.History(req.Task.ID)
+	if err != nil {
+		return err
+	}
+	res.History = changes
+	return nil
+}
+
+// each 逐个处理任务并汇总结果。只有一个任务时失败直接作为错误返回，与单任务请求的响应一致
+func (t *TaskToolImpl) each(items []*Task, res *TaskResponse, fn func(task *Task) (*Task, error)) error {
+	if len(items) == 1 {
+		task, err := fn(items[0])
 		if err != nil {
-			res.Status = "error"
-			res.Error = fmt.Sprintf("failed to list tasks: %v", err)
-			return res, nil
+			return err
 		}
-		res.TaskList = tasks
+		if task != nil {
+			res.TaskList = []*Task{task}
+		}
+		return nil
+	}
 
-	default:
-		res.Status = "error"
-		res.Error = fmt.Sprintf("unknown action: %s", req.Action)
+	failed := 0
+	for _, item := range items {
+		task, err := fn(item)
+		result := &TaskResult{ID: item.ID, Status: ResponseSuccess}
+		if err != nil {
+			failed++
+			result.Status, result.Error = ResponseError, err.Error()
+		} else if task != nil {
+			res.TaskList = append(res.TaskList, task)
+		}
+		res.Results = append(res.Results, result)
 	}
 
-	res.Status = "success"
-	return res, nil
+	switch failed {
+	case 0:
+	case len(items):
+		res.Status = ResponseError
+		res.Error = "all tasks failed, see results"
+	default:
+		res.Status = ResponsePartial
+		res.Error = fmt.Sprintf("%d of %d tasks failed, see results", failed, len(items))
+	}
+	return nil
 }
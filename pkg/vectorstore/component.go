@@ -0,0 +1,146 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+	"github.com/google/uuid"
+)
+
+type IndexerConfig struct {
+	Store     Store
+	Embedding embedding.Embedder
+	// BatchSize 每次调用 Embedder 时提交的文档数，默认 10
+	BatchSize int
+}
+
+// Indexer 将文档向量化后写入 Store，实现 indexer.Indexer
+type Indexer struct {
+	config *IndexerConfig
+}
+
+func NewIndexer(ctx context.Context, config *IndexerConfig) (*Indexer, error) {
+	if config == nil || config.Store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10
+	}
+	return &Indexer{config: config}, nil
+}
+
+func (i *Indexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	co := indexer.GetCommonOptions(&indexer.Options{Embedding: i.config.Embedding}, opts...)
+	if co.Embedding == nil {
+		return nil, fmt.Errorf("[vectorstore indexer] embedding not provided")
+	}
+
+	ids = make([]string, 0, len(docs))
+	for start := 0; start < len(docs); start += i.config.BatchSize {
+		end := min(start+i.config.BatchSize, len(docs))
+		batch := docs[start:end]
+
+		texts := make([]string, len(batch))
+		for j, doc := range batch {
+			if doc.ID == "" {
+				doc.ID = uuid.New().String()
+			}
+			texts[j] = doc.Content
+		}
+
+		vectors, err := co.Embedding.EmbedStrings(ctx, texts)
+		if err != nil {
+			return ids, fmt.Errorf("failed to embed documents: %w", err)
+		}
+		if len(vectors) != len(batch) {
+			return ids, fmt.Errorf("[vectorstore indexer] invalid return length of vector, got=%d, expected=%d", len(vectors), len(batch))
+		}
+
+		if err := i.config.Store.Upsert(ctx, batch, vectors); err != nil {
+			return ids, err
+		}
+		for _, doc := range batch {
+			ids = append(ids, doc.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (i *Indexer) GetType() string {
+	return "VectorStore"
+}
+
+type RetrieverConfig struct {
+	Store     Store
+	Embedding embedding.Embedder
+	// TopK 默认返回的最大文档数，默认 5
+	TopK int
+	// MinScore 默认最低相似度
+	MinScore float64
+	// Filter 默认过滤条件，会与单次检索的过滤条件合并
+	Filter *Filter
+}
+
+// Retriever 将查询向量化后在 Store 中检索，实现 retriever.Retriever。
+// 单次检索可通过 retriever.WithTopK、retriever.WithScoreThreshold 与 WithFilter 覆盖默认值
+type Retriever struct {
+	config *RetrieverConfig
+}
+
+type retrieverOptions struct {
+	Filter *Filter
+}
+
+// WithFilter 为单次检索指定元数据过滤条件
+func WithFilter(filter *Filter) retriever.Option {
+	return retriever.WrapImplSpecificOptFn(func(o *retrieverOptions) {
+		o.Filter = filter
+	})
+}
+
+func NewRetriever(ctx context.Context, config *RetrieverConfig) (*Retriever, error) {
+	if config == nil || config.Store == nil {
+		return nil, fmt.Errorf("store cannot be nil")
+	}
+	if config.TopK <= 0 {
+		config.TopK = 5
+	}
+	return &Retriever{config: config}, nil
+}
+
+func (r *Retriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	co := retriever.GetCommonOptions(&retriever.Options{
+		TopK:           &r.config.TopK,
+		ScoreThreshold: &r.config.MinScore,
+		Embedding:      r.config.Embedding,
+	}, opts...)
+	io := retriever.GetImplSpecificOptions(&retrieverOptions{}, opts...)
+
+	if co.Embedding == nil {
+		return nil, fmt.Errorf("[vectorstore retriever] embedding not provided")
+	}
+	vectors, err := co.Embedding.EmbedStrings(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("[vectorstore retriever] invalid return length of vector, got=%d, expected=1", len(vectors))
+	}
+
+	searchOpts := &SearchOptions{
+		TopK:   *co.TopK,
+		Filter: r.config.Filter.merge(io.Filter),
+	}
+	if co.ScoreThreshold != nil {
+		searchOpts.MinScore = *co.ScoreThreshold
+	}
+	return r.config.Store.Search(ctx, vectors[0], searchOpts)
+}
+
+func (r *Retriever) GetType() string {
+	return "VectorStore"
+}
@@ -0,0 +1,487 @@
+package vectorstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type MemoryConfig struct {
+	// Path 持久化文件路径，为空时只保存在内存中
+	Path string
+}
+
+// MemoryStore 纯 Go 实现的向量存储，暴力计算余弦相似度，适合开发和单元测试。
+// 持久化为快照 Path 与追加写入的日志 Path.log，每次写入只追加这一批的变更，
+// 日志超过快照大小（至少 minCompactSize）时合并进快照，合并期间不阻塞查询与写入
+type MemoryStore struct {
+	mu      sync.RWMutex
+	path    string
+	entries map[string]*memoryEntry
+
+	// log 与 logSize 由 mu 保护
+	log     *os.File
+	logSize int64
+	// snapshotSize 最近一次快照的大小，由 mu 保护
+	snapshotSize int64
+	// compactMu 同一时间只有一次合并
+	compactMu sync.Mutex
+}
+
+// minCompactSize 日志小于该值时不合并
+const minCompactSize = 16 << 20
+
+// memoryLogRecord 日志中的一条记录，即一次 Upsert 或 Delete
+type memoryLogRecord struct {
+	Upsert []*memoryEntry
+	Delete []string
+}
+
+type memoryEntry struct {
+	ID       string
+	Content  string
+	MetaData []byte // JSON
+	Vector   []float32
+	norm     float64
+
+	meta map[string]any
+}
+
+func NewMemoryStore(config *MemoryConfig) (*MemoryStore, error) {
+	if config == nil {
+		config = &MemoryConfig{}
+	}
+	s := &MemoryStore{
+		path:    config.Path,
+		entries: make(map[string]*memoryEntry),
+	}
+	if err := s.loadFromDisk(); err != nil {
+		return nil, err
+	}
+	if err := s.openLog(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *MemoryStore) Upsert(ctx context.Context, docs []*schema.Document, vectors [][]float64) error {
+	if len(docs) != len(vectors) {
+		return fmt.Errorf("docs and vectors length mismatch: %d != %d", len(docs), len(vectors))
+	}
+
+	entries := make([]*memoryEntry, 0, len(docs))
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document id cannot be empty")
+		}
+		meta, err := json.Marshal(doc.MetaData)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		e := &memoryEntry{
+			ID:       doc.ID,
+			Content:  doc.Content,
+			MetaData: meta,
+			Vector:   make([]float32, len(vectors[i])),
+		}
+		for j, v := range vectors[i] {
+			e.Vector[j] = float32(v)
+		}
+		e.init()
+		entries = append(entries, e)
+	}
+
+	s.mu.Lock()
+	for _, e := range entries {
+		s.entries[e.ID] = e
+	}
+	err := s.appendLog(&memoryLogRecord{Upsert: entries})
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.maybeCompact()
+	return nil
+}
+
+func (s *MemoryStore) Search(ctx context.Context, vector []float64, opts *SearchOptions) ([]*schema.Document, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	if opts.Filter != nil && opts.Filter.Raw != "" {
+		return nil, fmt.Errorf("raw filter expression is not supported by memory vector store")
+	}
+
+	var qNorm float64
+	for _, v := range vector {
+		qNorm += v * v
+	}
+	qNorm = math.Sqrt(qNorm)
+
+	type hit struct {
+		entry *memoryEntry
+		score float64
+	}
+
+	s.mu.RLock()
+	hits := make([]hit, 0, len(s.entries))
+	for _, e := range s.entries {
+		if !e.match(opts.Filter) || len(e.Vector) != len(vector) || e.norm == 0 || qNorm == 0 {
+			continue
+		}
+		var dot float64
+		for i, v := range e.Vector {
+			dot += float64(v) * vector[i]
+		}
+		score := dot / (e.norm * qNorm)
+		if score < opts.MinScore {
+			continue
+		}
+		hits = append(hits, hit{entry: e, score: score})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].score > hits[j].score
+	})
+	if opts.TopK > 0 && len(hits) > opts.TopK {
+		hits = hits[:opts.TopK]
+	}
+
+	docs := make([]*schema.Document, 0, len(hits))
+	for _, h := range hits {
+		doc := &schema.Document{
+			ID:       h.entry.ID,
+			Content:  h.entry.Content,
+			MetaData: make(map[string]any, len(h.entry.meta)),
+		}
+		for k, v := range h.entry.meta {
+			doc.MetaData[k] = v
+		}
+		docs = append(docs, doc.WithScore(h.score))
+	}
+	return docs, nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, filter *Filter) (int, error) {
+	if filter != nil && filter.Raw != "" {
+		return 0, fmt.Errorf("raw filter expression is not supported by memory vector store")
+	}
+
+	s.mu.Lock()
+	var removed []string
+	for id, e := range s.entries {
+		if e.match(filter) {
+			delete(s.entries, id)
+			removed = append(removed, id)
+		}
+	}
+	if len(removed) == 0 {
+		s.mu.Unlock()
+		return 0, nil
+	}
+	err := s.appendLog(&memoryLogRecord{Delete: removed})
+	s.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	s.maybeCompact()
+	return len(removed), nil
+}
+
+func (s *MemoryStore) Meetings(ctx context.Context) ([]MeetingInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, e := range s.entries {
+		id, _ := e.meta[MetaMeetingID].(string)
+		counts[id]++
+	}
+	return sortedMeetings(counts), nil
+}
+
+func (e *memoryEntry) init() {
+	var norm float64
+	for _, v := range e.Vector {
+		norm += float64(v) * float64(v)
+	}
+	e.norm = math.Sqrt(norm)
+	e.meta = make(map[string]any)
+	_ = json.Unmarshal(e.MetaData, &e.meta)
+}
+
+func (e *memoryEntry) match(f *Filter) bool {
+	if f.IsZero() {
+		return true
+	}
+	if f.MeetingID != "" {
+		if id, _ := e.meta[MetaMeetingID].(string); id != f.MeetingID {
+			return false
+		}
+	}
+	if f.Speaker != "" {
+		found := false
+		speakers, _ := e.meta[MetaSpeaker].([]any)
+		for _, sp := range speakers {
+			if sp == f.Speaker {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.DateFrom.IsZero() || !f.DateTo.IsZero() {
+		date, ok := e.meta[MetaDate].(float64)
+		if !ok {
+			return false
+		}
+		if !f.DateFrom.IsZero() && int64(date) < f.DateFrom.Unix() {
+			return false
+		}
+		if !f.DateTo.IsZero() && int64(date) > f.DateTo.Unix() {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *MemoryStore) logPath() string {
+	return s.path + ".log"
+}
+
+// compactingPath 合并期间被换下的日志，合并完成后删除
+func (s *MemoryStore) compactingPath() string {
+	return s.path + ".log.compacting"
+}
+
+// loadFromDisk 依次读取快照、合并中的日志与日志。合并中途退出时快照可能已经包含了
+// 合并中的日志，按顺序重放同样的变更结果不变
+func (s *MemoryStore) loadFromDisk() error {
+	if s.path == "" {
+		return nil
+	}
+	file, err := os.Open(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return fmt.Errorf("failed to open vector store file: %w", err)
+	default:
+		defer file.Close()
+		var entries []*memoryEntry
+		if err := gob.NewDecoder(file).Decode(&entries); err != nil {
+			return fmt.Errorf("failed to decode vector store file: %w", err)
+		}
+		for _, e := range entries {
+			e.init()
+			s.entries[e.ID] = e
+		}
+		if info, err := file.Stat(); err == nil {
+			s.snapshotSize = info.Size()
+		}
+	}
+
+	if _, err := s.replayLog(s.compactingPath()); err != nil {
+		return err
+	}
+	size, err := s.replayLog(s.logPath())
+	if err != nil {
+		return err
+	}
+	s.logSize = size
+	return nil
+}
+
+// replayLog 重放日志，返回完整记录的总长度。末尾不完整的记录（写入时进程退出）被忽略
+func (s *MemoryStore) replayLog(path string) (int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open vector store log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var size int64
+	for {
+		var length uint32
+		if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+			break
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			break
+		}
+		var record memoryLogRecord
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&record); err != nil {
+			return 0, fmt.Errorf("failed to decode vector store log: %w", err)
+		}
+		for _, e := range record.Upsert {
+			e.init()
+			s.entries[e.ID] = e
+		}
+		for _, id := range record.Delete {
+			delete(s.entries, id)
+		}
+		size += int64(4 + length)
+	}
+	return size, nil
+}
+
+// openLog 打开日志用于追加，并截掉末尾不完整的记录
+func (s *MemoryStore) openLog() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create vector store directory: %w", err)
+	}
+	file, err := os.OpenFile(s.logPath(), os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open vector store log: %w", err)
+	}
+	if err := file.Truncate(s.logSize); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to truncate vector store log: %w", err)
+	}
+	if _, err := file.Seek(s.logSize, io.SeekStart); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to seek vector store log: %w", err)
+	}
+	s.log = file
+	return nil
+}
+
+// appendLog 追加一条记录，每条记录为 4 字节长度加 gob 编码的内容，调用方需持有写锁
+func (s *MemoryStore) appendLog(record *memoryLogRecord) error {
+	if s.log == nil {
+		return nil
+	}
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4))
+	if err := gob.NewEncoder(&buf).Encode(record); err != nil {
+		return fmt.Errorf("failed to encode vector store log: %w", err)
+	}
+	data := buf.Bytes()
+	binary.LittleEndian.PutUint32(data, uint32(len(data)-4))
+	if _, err := s.log.Write(data); err != nil {
+		return fmt.Errorf("failed to write vector store log: %w", err)
+	}
+	if err := s.log.Sync(); err != nil {
+		return fmt.Errorf("failed to sync vector store log: %w", err)
+	}
+	s.logSize += int64(len(data))
+	return nil
+}
+
+// maybeCompact 日志超过快照大小时合并，写入总量与数据量成线性关系。
+// 持有写锁时只换下日志并复制条目指针，写快照不持有锁
+func (s *MemoryStore) maybeCompact() {
+	if s.log == nil || !s.compactMu.TryLock() {
+		return
+	}
+	defer s.compactMu.Unlock()
+
+	s.mu.Lock()
+	if s.logSize < max(s.snapshotSize, minCompactSize) {
+		s.mu.Unlock()
+		return
+	}
+	entries := make([]*memoryEntry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, e)
+	}
+	err := s.rotateLog()
+	s.mu.Unlock()
+	if err != nil {
+		log.Printf("[vectorstore] failed to rotate log: %v", err)
+		return
+	}
+
+	size, err := s.writeSnapshot(entries)
+	if err != nil {
+		// 换下的日志保留，下次启动时仍会重放，下次合并时再写快照
+		log.Printf("[vectorstore] failed to compact: %v", err)
+		return
+	}
+	if err := os.Remove(s.compactingPath()); err != nil {
+		log.Printf("[vectorstore] failed to remove compacted log: %v", err)
+	}
+	s.mu.Lock()
+	s.snapshotSize = size
+	s.mu.Unlock()
+}
+
+// rotateLog 将日志换下为合并中的日志并打开新的日志，调用方需持有写锁。
+// 上一次合并失败留下的合并中的日志会先并入当前日志，避免丢失
+func (s *MemoryStore) rotateLog() error {
+	if err := s.log.Close(); err != nil {
+		return err
+	}
+	if old, err := os.ReadFile(s.compactingPath()); err == nil {
+		current, err := os.ReadFile(s.logPath())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(s.logPath(), append(old, current...), 0644); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(s.logPath(), s.compactingPath()); err != nil {
+		return err
+	}
+	s.logSize = 0
+	return s.openLog()
+}
+
+// writeSnapshot 先写临时文件再重命名，避免写到一半时进程退出损坏数据，返回快照大小
+func (s *MemoryStore) writeSnapshot(entries []*memoryEntry) (int64, error) {
+	tmpFile := s.path + ".tmp"
+	file, err := os.Create(tmpFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	if err := gob.NewEncoder(file).Encode(entries); err != nil {
+		file.Close()
+		os.Remove(tmpFile)
+		return 0, fmt.Errorf("failed to encode vector store: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmpFile)
+		return 0, fmt.Errorf("failed to sync file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		os.Remove(tmpFile)
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmpFile)
+		return 0, fmt.Errorf("failed to close file: %w", err)
+	}
+	if err := os.Rename(tmpFile, s.path); err != nil {
+		os.Remove(tmpFile)
+		return 0, fmt.Errorf("failed to rename temp file: %w", err)
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,267 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/redis/go-redis/v9"
+
+	redispkg "meetingagent/pkg/redis"
+)
+
+type RedisConfig struct {
+	RedisAddr string
+	Dimension int
+}
+
+// RedisStore 基于 Redis Stack（RediSearch）的向量存储，文档以 hash 形式保存在 redispkg.RedisPrefix 下
+type RedisStore struct {
+	client *redis.Client
+	index  string
+}
+
+func NewRedisStore(ctx context.Context, config *RedisConfig) (*RedisStore, error) {
+	if err := redispkg.InitRedisIndex(ctx, &redispkg.Config{
+		RedisAddr: config.RedisAddr,
+		Dimension: config.Dimension,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to init redis index: %w", err)
+	}
+
+	// FT.SEARCH 的结果解析需要 RESP2
+	client := redis.NewClient(&redis.Options{
+		Addr:     config.RedisAddr,
+		Protocol: 2,
+	})
+	return &RedisStore{
+		client: client,
+		index:  redispkg.RedisPrefix + redispkg.IndexName,
+	}, nil
+}
+
+func (s *RedisStore) Upsert(ctx context.Context, docs []*schema.Document, vectors [][]float64) error {
+	if len(docs) != len(vectors) {
+		return fmt.Errorf("docs and vectors length mismatch: %d != %d", len(docs), len(vectors))
+	}
+
+	pipe := s.client.Pipeline()
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document id cannot be empty")
+		}
+		metadataBytes, err := json.Marshal(doc.MetaData)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+
+		fields := map[string]any{
+			redispkg.ContentField:  doc.Content,
+			redispkg.MetadataField: metadataBytes,
+			redispkg.VectorField:   vectorToBytes(vectors[i]),
+		}
+		if id, ok := doc.MetaData[MetaMeetingID].(string); ok {
+			fields[redispkg.MeetingIDField] = id
+		}
+		if speakers, ok := doc.MetaData[MetaSpeaker].([]string); ok {
+			fields[redispkg.SpeakerField] = strings.Join(speakers, ",")
+		}
+		if date, ok := doc.MetaData[MetaDate].(int64); ok {
+			fields[redispkg.DateField] = date
+		}
+
+		key := redispkg.RedisPrefix + doc.ID
+		pipe.Del(ctx, key)
+		pipe.HSet(ctx, key, fields)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to write documents: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Search(ctx context.Context, vector []float64, opts *SearchOptions) ([]*schema.Document, error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+	topK := opts.TopK
+	if topK <= 0 {
+		topK = 5
+	}
+
+	filter := buildFilterQuery(opts.Filter)
+	if filter == "" {
+		filter = "*"
+	}
+	query := fmt.Sprintf("(%s)=>[KNN %d @%s $vector AS %s]", filter, topK, redispkg.VectorField, redispkg.DistanceField)
+
+	result, err := s.client.FTSearchWithArgs(ctx, s.index, query, &redis.FTSearchOptions{
+		Return: []redis.FTSearchReturn{
+			{FieldName: redispkg.ContentField},
+			{FieldName: redispkg.MetadataField},
+			{FieldName: redispkg.DistanceField},
+		},
+		SortBy:         []redis.FTSearchSortBy{{FieldName: redispkg.DistanceField, Asc: true}},
+		Limit:          topK,
+		DialectVersion: 2,
+		Params:         map[string]any{"vector": vectorToBytes(vector)},
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to search documents: %w", err)
+	}
+
+	docs := make([]*schema.Document, 0, len(result.Docs))
+	for _, raw := range result.Docs {
+		doc := &schema.Document{
+			ID:       strings.TrimPrefix(raw.ID, redispkg.RedisPrefix),
+			MetaData: map[string]any{},
+		}
+		var score float64
+		for field, val := range raw.Fields {
+			switch field {
+			case redispkg.ContentField:
+				doc.Content = val
+			case redispkg.MetadataField:
+				if err := json.Unmarshal([]byte(val), &doc.MetaData); err != nil {
+					doc.MetaData[field] = val
+				}
+			case redispkg.DistanceField:
+				distance, err := strconv.ParseFloat(val, 64)
+				if err != nil {
+					continue
+				}
+				score = 1 - distance
+			}
+		}
+		if score < opts.MinScore {
+			continue
+		}
+		docs = append(docs, doc.WithScore(score))
+	}
+	return docs, nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, filter *Filter) (int, error) {
+	query := buildFilterQuery(filter)
+	if query == "" {
+		query = "*"
+	}
+
+	removed := 0
+	for {
+		result, err := s.client.FTSearchWithArgs(ctx, s.index, query, &redis.FTSearchOptions{
+			NoContent:      true,
+			Limit:          1000,
+			DialectVersion: 2,
+		}).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to search documents: %w", err)
+		}
+		if len(result.Docs) == 0 {
+			return removed, nil
+		}
+		keys := make([]string, 0, len(result.Docs))
+		for _, doc := range result.Docs {
+			keys = append(keys, doc.ID)
+		}
+		n, err := s.client.Del(ctx, keys...).Result()
+		if err != nil {
+			return removed, fmt.Errorf("failed to delete documents: %w", err)
+		}
+		removed += int(n)
+	}
+}
+
+func (s *RedisStore) Meetings(ctx context.Context) ([]MeetingInfo, error) {
+	counts := make(map[string]int)
+	iter := s.client.Scan(ctx, 0, redispkg.RedisPrefix+"*", 500).Iterator()
+	keys := make([]string, 0, 500)
+	flush := func() error {
+		if len(keys) == 0 {
+			return nil
+		}
+		pipe := s.client.Pipeline()
+		cmds := make([]*redis.StringCmd, len(keys))
+		for i, key := range keys {
+			cmds[i] = pipe.HGet(ctx, key, redispkg.MeetingIDField)
+		}
+		if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+			return fmt.Errorf("failed to read meeting ids: %w", err)
+		}
+		for _, cmd := range cmds {
+			counts[cmd.Val()]++
+		}
+		keys = keys[:0]
+		return nil
+	}
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == cap(keys) {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan documents: %w", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	return sortedMeetings(counts), nil
+}
+
+// buildFilterQuery 将过滤条件转换为 RediSearch 查询，多个条件之间为 AND
+func buildFilterQuery(f *Filter) string {
+	if f.IsZero() {
+		return ""
+	}
+	var parts []string
+	if raw := strings.TrimSpace(f.Raw); raw != "" {
+		parts = append(parts, "("+raw+")")
+	}
+	if f.MeetingID != "" {
+		parts = append(parts, fmt.Sprintf("@%s:{%s}", redispkg.MeetingIDField, escapeTag(f.MeetingID)))
+	}
+	if f.Speaker != "" {
+		parts = append(parts, fmt.Sprintf("@%s:{%s}", redispkg.SpeakerField, escapeTag(f.Speaker)))
+	}
+	if !f.DateFrom.IsZero() || !f.DateTo.IsZero() {
+		from, to := "-inf", "+inf"
+		if !f.DateFrom.IsZero() {
+			from = strconv.FormatInt(f.DateFrom.Unix(), 10)
+		}
+		if !f.DateTo.IsZero() {
+			to = strconv.FormatInt(f.DateTo.Unix(), 10)
+		}
+		parts = append(parts, fmt.Sprintf("@%s:[%s %s]", redispkg.DateField, from, to))
+	}
+	return strings.Join(parts, " ")
+}
+
+// escapeTag 转义 TAG 查询中的特殊字符
+func escapeTag(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(",.<>{}[]\"':;!@#$%^&*()-+=~|/\\ ", r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// vectorToBytes 索引的向量类型为 FLOAT32
+func vectorToBytes(vector []float64) []byte {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[i*4:], math.Float32bits(float32(v)))
+	}
+	return buf
+}
@@ -0,0 +1,132 @@
+package vectorstore
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+
+	"meetingagent/pkg/env"
+)
+
+// 文档元数据中用于过滤的 key，由 knowledgeindexing 在索引时写入
+const (
+	MetaMeetingID = "meeting_id"
+	MetaSpeaker   = "speaker"
+	MetaDate      = "date"
+)
+
+// Store 向量存储，knowledgeindexing 与 einoagent 两个 graph 通过它读写向量，
+// 目前有 Redis（RediSearch）与纯 Go 内存实现
+type Store interface {
+	// Upsert 写入文档及其向量，vectors 与 docs 一一对应，ID 相同的文档会被覆盖
+	Upsert(ctx context.Context, docs []*schema.Document, vectors [][]float64) error
+	// Search 按余弦相似度返回最相近的文档，文档分数为相似度
+	Search(ctx context.Context, vector []float64, opts *SearchOptions) ([]*schema.Document, error)
+	// Delete 删除满足过滤条件的文档，filter 为 nil 时删除全部，返回删除的文档数
+	Delete(ctx context.Context, filter *Filter) (int, error)
+	// Meetings 按会议统计已索引的文档数，按会议 ID 排序
+	Meetings(ctx context.Context) ([]MeetingInfo, error)
+}
+
+// MeetingInfo 单个会议在向量存储中的索引情况
+type MeetingInfo struct {
+	ID     string `json:"id"`
+	Chunks int    `json:"chunks"`
+}
+
+func sortedMeetings(counts map[string]int) []MeetingInfo {
+	meetings := make([]MeetingInfo, 0, len(counts))
+	for id, n := range counts {
+		meetings = append(meetings, MeetingInfo{ID: id, Chunks: n})
+	}
+	sort.Slice(meetings, func(i, j int) bool {
+		return meetings[i].ID < meetings[j].ID
+	})
+	return meetings
+}
+
+type SearchOptions struct {
+	TopK int
+	// MinScore 最低相似度，低于该值的文档不返回
+	MinScore float64
+	Filter   *Filter
+}
+
+// Filter 元数据过滤条件，零值字段表示不过滤
+type Filter struct {
+	MeetingID string
+	Speaker   string
+	DateFrom  time.Time
+	DateTo    time.Time
+	// Raw 后端原生的过滤表达式（Redis 为 RediSearch 查询），会与其他条件取交集，
+	// 内存实现不支持
+	Raw string
+}
+
+// IsZero 是否没有任何过滤条件
+func (f *Filter) IsZero() bool {
+	return f == nil || *f == (Filter{})
+}
+
+// merge 合并两组过滤条件，other 中的非零字段优先，Raw 取交集
+func (f *Filter) merge(other *Filter) *Filter {
+	if f.IsZero() {
+		return other
+	}
+	if other.IsZero() {
+		return f
+	}
+	merged := *f
+	if other.MeetingID != "" {
+		merged.MeetingID = other.MeetingID
+	}
+	if other.Speaker != "" {
+		merged.Speaker = other.Speaker
+	}
+	if !other.DateFrom.IsZero() {
+		merged.DateFrom = other.DateFrom
+	}
+	if !other.DateTo.IsZero() {
+		merged.DateTo = other.DateTo
+	}
+	if other.Raw != "" {
+		if merged.Raw != "" {
+			merged.Raw = "(" + merged.Raw + ") (" + other.Raw + ")"
+		} else {
+			merged.Raw = other.Raw
+		}
+	}
+	return &merged
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultStore Store
+	defaultErr   error
+)
+
+// Default 返回进程内共享的向量存储，由环境变量选择实现：
+//   - VECTOR_STORE: redis（默认）或 memory
+//   - VECTOR_STORE_PATH: memory 实现的持久化文件，默认 data/vectors.gob
+func Default(ctx context.Context) (Store, error) {
+	defaultOnce.Do(func() {
+		switch kind := env.GetString("VECTOR_STORE", "redis"); kind {
+		case "redis":
+			defaultStore, defaultErr = NewRedisStore(ctx, &RedisConfig{
+				RedisAddr: env.GetString("REDIS_ADDR", "localhost:6379"),
+				Dimension: env.GetInt("ARK_EMBEDDING_DIM", 4096),
+			})
+		case "memory":
+			defaultStore, defaultErr = NewMemoryStore(&MemoryConfig{
+				Path: env.GetString("VECTOR_STORE_PATH", "data/vectors.gob"),
+			})
+		default:
+			defaultErr = fmt.Errorf("unknown vector store: %s", kind)
+		}
+	})
+	return defaultStore, defaultErr
+}
@@ -0,0 +1,265 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rag
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/compose"
+
+	"meetingagent/knowledgeindexing"
+	"meetingagent/pkg/env"
+	"meetingagent/pkg/vectorstore"
+)
+
+// maxFileRunes 超过该长度的会议记录先切分为多个文件再索引
+const maxFileRunes = 4096
+
+// Progress 单个文件索引结束后的进度
+type Progress struct {
+	Total     int
+	Done      int
+	File      string
+	MeetingID string
+	Chunks    int
+	Elapsed   time.Duration
+	Err       error
+}
+
+// ProgressFunc 进度回调，会在多个 worker 中被调用，但调用之间互斥
+type ProgressFunc func(p Progress)
+
+// LogProgress 将进度写入日志，服务端索引时使用
+func LogProgress(p Progress) {
+	if p.Err != nil {
+		log.Printf("[index] [%d/%d] %s failed: %v", p.Done, p.Total, p.File, p.Err)
+		return
+	}
+	log.Printf("[index] [%d/%d] %s -> %s, %d chunks, %v", p.Done, p.Total, p.File, p.MeetingID, p.Chunks, p.Elapsed)
+}
+
+// IndexMarkdownFiles 索引 dir 下的所有 markdown 文件
+func IndexMarkdownFiles(ctx context.Context, dir string) error {
+	return IndexFiles(ctx, []string{dir}, LogProgress)
+}
+
+// IndexFiles 索引 paths 中的 markdown 文件，目录会被递归展开。
+// 文件之间由 INDEX_WORKERS 个 worker 并发处理（默认 4），每个会议在索引前会先删除已有的向量，
+// 因此重复索引同一会议不会产生重复文档。任一文件失败时取消其余文件并返回第一个错误
+func IndexFiles(ctx context.Context, paths []string, progress ProgressFunc) error {
+	files, err := collectMarkdownFiles(paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	runner, err := knowledgeindexing.BuildKnowledgeIndexing(ctx)
+	if err != nil {
+		return fmt.Errorf("build index graph failed: %w", err)
+	}
+	store, err := vectorstore.Default(ctx)
+	if err != nil {
+		return err
+	}
+
+	workers := env.GetInt("INDEX_WORKERS", 4)
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int
+		firstErr error
+	)
+	report := func(p Progress) {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		p.Done, p.Total = done, len(files)
+		if p.Err != nil && firstErr == nil {
+			firstErr = p.Err
+			cancel()
+		}
+		if progress != nil {
+			progress(p)
+		}
+	}
+
+	jobs := make(chan string)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range jobs {
+				start := time.Now()
+				meetingID := knowledgeindexing.MeetingIDFromPath(path)
+				chunks, err := indexMarkdownFile(ctx, runner, store, path, meetingID)
+				report(Progress{
+					File:      path,
+					MeetingID: meetingID,
+					Chunks:    chunks,
+					Elapsed:   time.Since(start),
+					Err:       err,
+				})
+			}
+		}()
+	}
+
+feed:
+	for _, path := range files {
+		select {
+		case jobs <- path:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// Reindex 清空向量存储后重新索引 paths
+func Reindex(ctx context.Context, paths []string, progress ProgressFunc) (removed int, err error) {
+	store, err := vectorstore.Default(ctx)
+	if err != nil {
+		return 0, err
+	}
+	removed, err = store.Delete(ctx, nil)
+	if err != nil {
+		return removed, fmt.Errorf("clear vector store failed: %w", err)
+	}
+	return removed, IndexFiles(ctx, paths, progress)
+}
+
+// ListMeetings 列出已索引的会议及其文档数
+func ListMeetings(ctx context.Context) ([]vectorstore.MeetingInfo, error) {
+	store, err := vectorstore.Default(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return store.Meetings(ctx)
+}
+
+// DeleteMeeting 删除会议的全部向量，返回删除的文档数
+func DeleteMeeting(ctx context.Context, meetingID string) (int, error) {
+	if meetingID == "" {
+		return 0, fmt.Errorf("meeting id cannot be empty")
+	}
+	store, err := vectorstore.Default(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return store.Delete(ctx, &vectorstore.Filter{MeetingID: meetingID})
+}
+
+// collectMarkdownFiles 展开目录并过滤出需要索引的 markdown 文件。
+// 切分产生的 .partN.md 会在索引原文件时重新生成，这里跳过
+func collectMarkdownFiles(paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]struct{})
+	add := func(path string) {
+		if !strings.HasSuffix(path, ".md") {
+			fmt.Printf("[skip] not a md file: %s\n", path)
+			return
+		}
+		if knowledgeindexing.IsPartFile(path) {
+			return
+		}
+		if _, ok := seen[path]; ok {
+			return
+		}
+		seen[path] = struct{}{}
+		files = append(files, path)
+	}
+
+	for _, root := range paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, fmt.Errorf("stat %s failed: %w", root, err)
+		}
+		if !info.IsDir() {
+			add(root)
+			continue
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return fmt.Errorf("walk dir failed: %w", err)
+			}
+			if !d.IsDir() {
+				add(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func indexMarkdownFile(ctx context.Context, runner compose.Runnable[document.Source, []string], store vectorstore.Store, path, meetingID string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("read file %s failed: %w", path, err)
+	}
+
+	// 如果文件内容超过 maxFileRunes，切分为多个文件
+	var filesToIndex []string
+	if len([]rune(string(content))) > maxFileRunes {
+		filesToIndex, err = SplitMarkdownFile(path, 5)
+		if err != nil {
+			return 0, fmt.Errorf("split file %s failed: %w", path, err)
+		}
+	} else {
+		filesToIndex = []string{path}
+	}
+
+	if _, err := store.Delete(ctx, &vectorstore.Filter{MeetingID: meetingID}); err != nil {
+		return 0, fmt.Errorf("delete old vectors of %s failed: %w", meetingID, err)
+	}
+
+	// 调用 runner 进行索引
+	chunks := 0
+	for _, filePath := range filesToIndex {
+		ids, err := runner.Invoke(ctx, document.Source{URI: filePath})
+		if err != nil {
+			return chunks, fmt.Errorf("invoke index graph for file %s failed: %w", filePath, err)
+		}
+		chunks += len(ids)
+	}
+	return chunks, nil
+}